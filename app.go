@@ -1,5 +1,7 @@
 package tinygo
 
+import "context"
+
 // App 应用接口
 type App interface {
 	// Name 返回App名称
@@ -9,3 +11,24 @@ type App interface {
 	// Run 应用运行接口
 	Run() error
 }
+
+// 可停止的App,Manager停止时会按照依赖顺序的逆序调用Stop,没有依赖关系的App按照添加顺序的逆序停止
+type StoppableApp interface {
+	App
+	// Stop 停止应用,需要在ctx结束前返回,停止后Run应当返回
+	Stop(ctx context.Context) error
+}
+
+// 拥有依赖的App,依赖的App全部就绪后当前App才会运行
+type DependentApp interface {
+	App
+	// Dependencies 返回当前App依赖的App名称列表
+	Dependencies() []string
+}
+
+// 可报告就绪状态的App,没有实现该接口的App在开始运行后立即视为就绪
+type ReadyApp interface {
+	App
+	// Ready 返回一个在App就绪后关闭的通道
+	Ready() <-chan struct{}
+}
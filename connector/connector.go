@@ -1,7 +1,10 @@
 //  Package connector 实现了基本的连接器接口
 package connector
 
-import "sync"
+import (
+	"strings"
+	"sync"
+)
 
 // Dispatcher 调度器
 type Dispatcher interface {
@@ -25,6 +28,24 @@ type Connector interface {
 	SetDispatcher(dispatcher Dispatcher)
 }
 
+// 可重新运行的连接器
+type ResettableConnector interface {
+	Connector
+	// Reset 重置连接器的停止状态,使Run返回后的连接器可以再次运行
+	Reset()
+}
+
+// 可重新加载证书的连接器
+type ReloadableConnector interface {
+	Connector
+	// Reload 重新加载证书,加载失败时继续使用原有证书
+	Reload() error
+	// ReloadError 返回最近一次重新加载的错误
+	ReloadError() error
+	// SetReloadHandler 设置重新加载失败时的回调
+	SetReloadHandler(handler func(err error))
+}
+
 // 连接器创建器
 //  suorce: 连接器监听位置(例如:127.0.0.1:9999表示监听127.0.0.1上的9999端口)
 type ConnectorCreator func(source string) (Connector, error)
@@ -53,3 +74,17 @@ func Register(kind string, creator ConnectorCreator) {
 	defer mu.Unlock()
 	creators[kind] = creator
 }
+
+// parseSource 解析连接器source,source格式如: 127.0.0.1:8080;Key1=Value1;Key2=Value2
+//  return:(监听地址,小写key到value的映射)
+func parseSource(source string) (string, map[string]string) {
+	var sources = strings.Split(source, ";")
+	var info = make(map[string]string, len(sources)-1)
+	for i := 1; i < len(sources); i++ {
+		var kv = strings.SplitN(sources[i], "=", 2)
+		if len(kv) == 2 {
+			info[strings.ToLower(strings.TrimSpace(kv[0]))] = strings.TrimSpace(kv[1])
+		}
+	}
+	return strings.TrimSpace(sources[0]), info
+}
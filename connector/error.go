@@ -28,6 +28,12 @@ const (
 	ErrorInvalidConnectorCreator Error = "ErrorInvalidConnectorCreator(N10000):无效的连接创建器"
 	ErrorInvalidKind             Error = "ErrorInvalidKind(N10010):无效的连接类型(%s)"
 	ErrorFailToStop              Error = "ErrorFailToStop(N10020):无法停止连接器(%s)"
+	ErrorDrainTimeout            Error = "ErrorDrainTimeout(N10021):连接器(%s)在%s内未能处理完全部请求,剩余连接已被强制关闭"
 	ErrorInvalidDispatcher       Error = "ErrorInvalidDispatcher(N10030):无效的Dispatcher,无法启动Connector(%s)"
-	ErrorParamNotFound           Error = "ErrorParamNotFound(N10100):source中没有%s,无法创建https连接器"
+	ErrorParamNotFound           Error = "ErrorParamNotFound(N10100):source中没有%s,无法创建连接器"
+	ErrorInvalidParam            Error = "ErrorInvalidParam(N10110):source中%s的值(%s)无效"
+	ErrorInvalidFd               Error = "ErrorInvalidFd(N10120):无效的文件描述符(%s)"
+	ErrorNoInheritedListener     Error = "ErrorNoInheritedListener(N10121):当前进程没有继承指定的监听器(%s)"
+	ErrorInvalidProxyHeader      Error = "ErrorInvalidProxyHeader(N10140):来自%s的连接的PROXY协议头无效:%v"
+	ErrorInvalidCert             Error = "ErrorInvalidCert(N10130):无法加载证书(%s):%v"
 )
@@ -0,0 +1,110 @@
+package connector
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// systemd传递的第一个文件描述符
+const listenFdsStart = 3
+
+// 文件描述符连接器,使用由父进程(例如systemd socket activation)传入的监听器
+type FdConnector struct {
+	HttpConnector
+	file *os.File //继承的文件描述符,第一次运行时打开,重置后再次运行时继续使用
+}
+
+// NewFdConnector 创建文件描述符连接器
+//  source:格式如: systemd;DrainTimeout=30
+//   source的第一段可以为以下几种形式:
+//    (1)数字:直接使用该文件描述符
+//    (2)systemd:使用LISTEN_FDS传入的第一个监听器
+//    (3)systemd:数字:使用LISTEN_FDS传入的指定序号(从0开始)的监听器
+//    (4)systemd:名称:使用LISTEN_FDNAMES中指定名称的监听器
+//   Cert,Key,SNI:证书设置,可选,设置后使用https,tls参数与https连接器相同
+//   其他可选参数与http连接器相同
+func NewFdConnector(source string) (Connector, error) {
+	var addr, info = parseSource(source)
+	if addr == "" {
+		return nil, ErrorParamNotFound.Format("文件描述符").Error()
+	}
+	var c = new(FdConnector)
+	c.addr = addr
+	var err error
+	c.tlsConfig, err = parseTls(info)
+	if err != nil {
+		return nil, err
+	}
+	err = c.parseInfo(info)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run 运行(接受连接并进行处理,阻塞)
+func (this *FdConnector) Run() error {
+	return this.serve("fd", func(server *http.Server) error {
+		if this.file == nil {
+			var fd, err = ListenFd(this.addr)
+			if err != nil {
+				return err
+			}
+			this.file = os.NewFile(uintptr(fd), "fd"+strconv.Itoa(fd))
+		}
+		//FileListener使用文件描述符的副本,停止时关闭副本,继承的文件描述符保持打开以便重新运行
+		var l, err = net.FileListener(this.file)
+		if err != nil {
+			return err
+		}
+		return this.serveListener(server, l)
+	})
+}
+
+// Stop 停止运行,不再接受新的连接,并等待正在处理的请求完成
+func (this *FdConnector) Stop() error {
+	return this.stop("fd")
+}
+
+// ListenFd 解析文件描述符连接器的地址,返回相应的文件描述符
+//  addr:格式参考NewFdConnector
+func ListenFd(addr string) (int, error) {
+	if !strings.HasPrefix(addr, "systemd") {
+		var fd, err = strconv.Atoi(addr)
+		if err != nil || fd < 0 {
+			return 0, ErrorInvalidFd.Format(addr).Error()
+		}
+		return fd, nil
+	}
+	var name = strings.TrimPrefix(strings.TrimPrefix(addr, "systemd"), ":")
+	//LISTEN_PID必须是当前进程
+	var pid, err = strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return 0, ErrorNoInheritedListener.Format(addr).Error()
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return 0, ErrorNoInheritedListener.Format(addr).Error()
+	}
+	var index = 0
+	if name != "" {
+		index, err = strconv.Atoi(name)
+		if err != nil {
+			//按名称查找
+			index = -1
+			for i, n := range strings.Split(os.Getenv("LISTEN_FDNAMES"), ":") {
+				if n == name {
+					index = i
+					break
+				}
+			}
+		}
+	}
+	if index < 0 || index >= count {
+		return 0, ErrorNoInheritedListener.Format(addr).Error()
+	}
+	return listenFdsStart + index, nil
+}
@@ -0,0 +1,95 @@
+package connector
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestListenFd(t *testing.T) {
+	var pid = strconv.Itoa(os.Getpid())
+	var cases = []struct {
+		addr  string
+		pid   string
+		fds   string
+		names string
+		fd    int
+		ok    bool
+	}{
+		{"7", "", "", "", 7, true},
+		{"-1", "", "", "", 0, false},
+		{"abc", "", "", "", 0, false},
+		{"systemd", pid, "2", "", 3, true},
+		{"systemd:1", pid, "2", "", 4, true},
+		{"systemd:2", pid, "2", "", 0, false},
+		{"systemd:web", pid, "2", "admin:web", 4, true},
+		{"systemd:api", pid, "2", "admin:web", 0, false},
+		{"systemd", "1", "2", "", 0, false},
+		{"systemd", pid, "0", "", 0, false},
+	}
+	for _, c := range cases {
+		t.Setenv("LISTEN_PID", c.pid)
+		t.Setenv("LISTEN_FDS", c.fds)
+		t.Setenv("LISTEN_FDNAMES", c.names)
+		var fd, err = ListenFd(c.addr)
+		if (err == nil) != c.ok || fd != c.fd {
+			t.Errorf("%s: expected (%d, %v), got (%d, %v)", c.addr, c.fd, c.ok, fd, err)
+		}
+	}
+}
+
+func TestFdConnector(t *testing.T) {
+	var l, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var addr = l.Addr().String()
+	f, err := l.(*net.TCPListener).File()
+	l.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	conn, err := NewFdConnector(strconv.Itoa(int(f.Fd())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//连接器与测试共用同一个文件描述符,只关闭一次
+	defer conn.(*FdConnector).file.Close()
+	conn.SetDispatcher(&testDispatcher{func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fd"))
+	}})
+	//重置后再次运行需要继续使用继承的文件描述符
+	for i := 0; i < 2; i++ {
+		var result = make(chan error, 1)
+		go func() {
+			result <- conn.Run()
+		}()
+		var body string
+		if !waitFor(func() bool {
+			var resp, err = http.Get("http://" + addr + "/")
+			if err != nil {
+				return false
+			}
+			defer resp.Body.Close()
+			var data, _ = ioutil.ReadAll(resp.Body)
+			body = string(data)
+			return true
+		}) {
+			t.Fatalf("run %d: fd connector did not serve the inherited listener", i)
+		}
+		if body != "fd" {
+			t.Errorf("run %d: unexpected body %q", i, body)
+		}
+		if err = conn.Stop(); err != nil {
+			t.Fatal(err)
+		}
+		if err = <-result; err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+		conn.(ResettableConnector).Reset()
+	}
+}
@@ -1,10 +1,19 @@
 package connector
 
 import (
+	"context"
+	"net"
 	"net/http"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+// 默认的停止等待时间,超过该时间仍未完成的请求将被强制关闭
+const DefaultDrainTimeout = 30 * time.Second
+
 // Http上下文
 type HttpContext struct {
 	Request        *http.Request       //http请求
@@ -28,14 +37,92 @@ func (this *HttpHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 
 // Http连接器
 type HttpConnector struct {
-	server     *http.Server //http服务
-	addr       string       //监听地址
-	dispatcher Dispatcher
+	server       *http.Server  //http服务
+	addr         string        //监听地址
+	dispatcher   Dispatcher    //调度器
+	drainTimeout time.Duration //停止时等待请求完成的最长时间
+	options      HttpOptions   //http服务设置
+	tlsConfig    *tlsStore     //tls设置,为nil时不使用tls
+	proxy        bool          //是否使用PROXY协议
+	proxyTimeout time.Duration //读取PROXY协议头的超时时间
+	mu           sync.Mutex    //保护server和stopped
+	stopped      bool          //是否已经停止
+	drained      chan struct{} //停止完成后关闭
+}
+
+// http服务设置,值为0时表示不限制
+type HttpOptions struct {
+	ReadTimeout       time.Duration //读取整个请求(包括请求体)的超时时间
+	ReadHeaderTimeout time.Duration //读取请求头的超时时间
+	WriteTimeout      time.Duration //写入响应的超时时间
+	IdleTimeout       time.Duration //keep-alive连接等待下一个请求的超时时间
+	MaxHeaderBytes    int           //请求头的最大字节数,为0时使用http.DefaultMaxHeaderBytes
 }
 
 // NewHttpConnector 创建Http连接器
+//  source:格式如: 127.0.0.1:8080;DrainTimeout=30;ReadTimeout=30;WriteTimeout=30;IdleTimeout=120;MaxHeaderBytes=1048576
+//   DrainTimeout:停止时等待请求完成的最长时间,单位为秒,可选,默认为30
+//   ReadTimeout:读取整个请求的超时时间,单位为秒,可选,默认为0(不限制)
+//   ReadHeaderTimeout:读取请求头的超时时间,单位为秒,可选,默认为0(不限制)
+//   WriteTimeout:写入响应的超时时间,单位为秒,可选,默认为0(不限制)
+//   IdleTimeout:keep-alive连接的空闲超时时间,单位为秒,可选,默认为0(不限制)
+//   MaxHeaderBytes:请求头的最大字节数,可选,默认为0(使用http.DefaultMaxHeaderBytes)
+//   ProxyProtocol:是否要求每个连接以PROXY协议(v1或v2)头开始,可选,默认为false
+//   ProxyHeaderTimeout:读取PROXY协议头的超时时间,单位为秒,可选,默认为5
 func NewHttpConnector(source string) (Connector, error) {
-	return &HttpConnector{nil, source, nil}, nil
+	var addr, info = parseSource(source)
+	var c = new(HttpConnector)
+	c.addr = addr
+	var err = c.parseInfo(info)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseInfo 解析source中的可选参数
+func (this *HttpConnector) parseInfo(info map[string]string) error {
+	this.drainTimeout = DefaultDrainTimeout
+	this.proxyTimeout = DefaultProxyHeaderTimeout
+	this.drained = make(chan struct{})
+	var params = []struct {
+		name  string
+		value *time.Duration
+	}{
+		{"DrainTimeout", &this.drainTimeout},
+		{"ReadTimeout", &this.options.ReadTimeout},
+		{"ReadHeaderTimeout", &this.options.ReadHeaderTimeout},
+		{"WriteTimeout", &this.options.WriteTimeout},
+		{"IdleTimeout", &this.options.IdleTimeout},
+		{"ProxyHeaderTimeout", &this.proxyTimeout},
+	}
+	for _, p := range params {
+		var v, ok = info[strings.ToLower(p.name)]
+		if ok {
+			var second, err = strconv.Atoi(v)
+			if err != nil || second < 0 {
+				return ErrorInvalidParam.Format(p.name, v).Error()
+			}
+			*p.value = time.Duration(second) * time.Second
+		}
+	}
+	var v, ok = info["maxheaderbytes"]
+	if ok {
+		var size, err = strconv.Atoi(v)
+		if err != nil || size < 0 {
+			return ErrorInvalidParam.Format("MaxHeaderBytes", v).Error()
+		}
+		this.options.MaxHeaderBytes = size
+	}
+	v, ok = info["proxyprotocol"]
+	if ok {
+		var proxy, err = strconv.ParseBool(v)
+		if err != nil {
+			return ErrorInvalidParam.Format("ProxyProtocol", v).Error()
+		}
+		this.proxy = proxy
+	}
+	return nil
 }
 
 // Init 初始化连接器设置
@@ -45,16 +132,150 @@ func (this *HttpConnector) Init() error {
 
 // Run 运行(接受连接并进行处理,阻塞)
 func (this *HttpConnector) Run() error {
+	return this.serve("http", func(server *http.Server) error {
+		var addr = this.addr
+		if addr == "" {
+			addr = ":http"
+		}
+		var l, err = net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		return this.serveListener(server, l)
+	})
+}
+
+// serveListener 使用l接受连接,启用PROXY协议时先解析协议头,server设置了tls时使用tls
+func (this *HttpConnector) serveListener(server *http.Server, l net.Listener) error {
+	if this.proxy {
+		var pl = NewProxyListener(l)
+		pl.HeaderTimeout = this.proxyTimeout
+		l = pl
+	}
+	if server.TLSConfig != nil {
+		return server.ServeTLS(l, "", "")
+	}
+	return server.Serve(l)
+}
+
+// serve 创建http服务并使用listen开始监听,调用Stop后等待停止完成再返回nil
+func (this *HttpConnector) serve(kind string, listen func(server *http.Server) error) error {
 	if this.dispatcher == nil {
-		panic(ErrorInvalidDispatcher.Format("http"))
+		panic(ErrorInvalidDispatcher.Format(kind))
 	}
-	this.server = &http.Server{Addr: this.addr, Handler: &HttpHandler{this.dispatcher}}
-	return this.server.ListenAndServe()
+	this.mu.Lock()
+	if this.stopped {
+		this.mu.Unlock()
+		return nil
+	}
+	var server = &http.Server{
+		Addr:              this.addr,
+		Handler:           &HttpHandler{this.dispatcher},
+		ReadTimeout:       this.options.ReadTimeout,
+		ReadHeaderTimeout: this.options.ReadHeaderTimeout,
+		WriteTimeout:      this.options.WriteTimeout,
+		IdleTimeout:       this.options.IdleTimeout,
+		MaxHeaderBytes:    this.options.MaxHeaderBytes,
+	}
+	if this.tlsConfig != nil {
+		server.TLSConfig = this.tlsConfig.Config()
+		var done = make(chan struct{})
+		defer close(done)
+		this.tlsConfig.watch(done)
+	}
+	this.server = server
+	var drained = this.drained
+	this.mu.Unlock()
+	var err = listen(server)
+	if err == http.ErrServerClosed {
+		//等待正在处理的请求完成
+		<-drained
+		return nil
+	}
+	return err
 }
 
-// Stop 停止运行
+// Stop 停止运行,不再接受新的连接,并等待正在处理的请求完成
 func (this *HttpConnector) Stop() error {
-	return ErrorFailToStop.Format("http").Error()
+	return this.stop("http")
+}
+
+// stop 停止http服务,超过drainTimeout时强制关闭所有连接
+func (this *HttpConnector) stop(kind string) error {
+	this.mu.Lock()
+	if this.stopped {
+		this.mu.Unlock()
+		return ErrorFailToStop.Format(kind).Error()
+	}
+	this.stopped = true
+	var server = this.server
+	this.mu.Unlock()
+	defer close(this.drained)
+	if server == nil {
+		//尚未运行
+		return nil
+	}
+	var ctx, cancel = context.WithTimeout(context.Background(), this.drainTimeout)
+	defer cancel()
+	var err = server.Shutdown(ctx)
+	if err == context.DeadlineExceeded {
+		server.Close()
+		return ErrorDrainTimeout.Format(kind, this.drainTimeout).Error()
+	}
+	return err
+}
+
+// Reset 重置停止状态,使Run返回后的连接器可以再次运行,只能在Run返回后调用
+func (this *HttpConnector) Reset() {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.stopped = false
+	this.server = nil
+	this.drained = make(chan struct{})
+}
+
+// Reload 重新加载tls证书和客户端CA,加载失败时继续使用原有证书
+//  运行中的连接器在收到SIGHUP信号时也会重新加载
+func (this *HttpConnector) Reload() error {
+	if this.tlsConfig == nil {
+		return nil
+	}
+	return this.tlsConfig.reload()
+}
+
+// ReloadError 返回最近一次重新加载tls证书(包括收到SIGHUP时的重新加载)的错误,成功或未使用tls时返回nil
+func (this *HttpConnector) ReloadError() error {
+	if this.tlsConfig == nil {
+		return nil
+	}
+	return this.tlsConfig.ReloadError()
+}
+
+// SetReloadHandler 设置重新加载tls证书失败时的回调,回调在接收信号的goroutine中调用
+func (this *HttpConnector) SetReloadHandler(handler func(err error)) {
+	if this.tlsConfig != nil {
+		this.tlsConfig.SetReloadHandler(handler)
+	}
+}
+
+// SetDrainTimeout 设置停止时等待请求完成的最长时间
+func (this *HttpConnector) SetDrainTimeout(timeout time.Duration) {
+	this.drainTimeout = timeout
+}
+
+// DrainTimeout 返回停止时等待请求完成的最长时间
+func (this *HttpConnector) DrainTimeout() time.Duration {
+	return this.drainTimeout
+}
+
+// Options 返回http服务设置
+func (this *HttpConnector) Options() HttpOptions {
+	return this.options
+}
+
+// SetOptions 设置http服务设置,在Run之前设置有效
+func (this *HttpConnector) SetOptions(options HttpOptions) {
+	this.options = options
 }
 
 // Dispatcher 返回当前调度器
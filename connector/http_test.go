@@ -0,0 +1,133 @@
+package connector
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 使用http.Handler处理请求的调度器
+type testDispatcher struct {
+	handler http.HandlerFunc
+}
+
+func (this *testDispatcher) Dispatch(segments []string, data interface{}) {
+	var c = data.(*HttpContext)
+	this.handler(c.ResponseWriter, c.Request)
+}
+
+// runTestConnector 在随机端口上运行c,返回监听地址和Run的结果
+func runTestConnector(t *testing.T, c *HttpConnector, handler http.HandlerFunc) (string, <-chan error) {
+	var l, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetDispatcher(&testDispatcher{handler})
+	var result = make(chan error, 1)
+	go func() {
+		result <- c.serve("http", func(server *http.Server) error {
+			return c.serveListener(server, l)
+		})
+	}()
+	return l.Addr().String(), result
+}
+
+func TestHttpStopDrains(t *testing.T) {
+	var conn, _ = NewHttpConnector("127.0.0.1:0")
+	var c = conn.(*HttpConnector)
+	var started = make(chan struct{})
+	var finished int32
+	var addr, result = runTestConnector(t, c, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		atomic.StoreInt32(&finished, 1)
+		w.Write([]byte("done"))
+	})
+	var body = make(chan string, 1)
+	go func() {
+		var resp, err = http.Get("http://" + addr + "/")
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		var data, _ = ioutil.ReadAll(resp.Body)
+		body <- string(data)
+	}()
+	<-started
+	if err := c.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&finished) != 1 {
+		t.Error("Stop returned before the in-flight request finished")
+	}
+	if err := <-result; err != nil {
+		t.Errorf("Run should return nil after Stop, got %v", err)
+	}
+	if b := <-body; b != "done" {
+		t.Errorf("in-flight request was not completed: %s", b)
+	}
+	if _, err := http.Get("http://" + addr + "/"); err == nil {
+		t.Error("stopped connector still accepts connections")
+	}
+}
+
+func TestHttpStopDrainTimeout(t *testing.T) {
+	var conn, _ = NewHttpConnector("127.0.0.1:0")
+	var c = conn.(*HttpConnector)
+	c.SetDrainTimeout(50 * time.Millisecond)
+	var started = make(chan struct{})
+	var release = make(chan struct{})
+	defer close(release)
+	var addr, result = runTestConnector(t, c, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	go http.Get("http://" + addr + "/")
+	<-started
+	var begin = time.Now()
+	if err := c.Stop(); err == nil {
+		t.Error("expected drain timeout error")
+	}
+	if time.Since(begin) > time.Second {
+		t.Error("Stop did not respect the drain timeout")
+	}
+	<-result
+}
+func TestHttpOptions(t *testing.T) {
+	var conn, err = NewHttpConnector("127.0.0.1:0;ReadTimeout=1;ReadHeaderTimeout=2;WriteTimeout=3;IdleTimeout=4;MaxHeaderBytes=4096;DrainTimeout=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c = conn.(*HttpConnector)
+	var addr, result = runTestConnector(t, c, func(w http.ResponseWriter, r *http.Request) {})
+	resp, err := http.Get("http://" + addr + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	c.mu.Lock()
+	var server = c.server
+	c.mu.Unlock()
+	if server.ReadTimeout != time.Second || server.ReadHeaderTimeout != 2*time.Second ||
+		server.WriteTimeout != 3*time.Second || server.IdleTimeout != 4*time.Second || server.MaxHeaderBytes != 4096 {
+		t.Errorf("options not applied to http.Server: %+v", c.options)
+	}
+	if c.DrainTimeout() != 5*time.Second {
+		t.Errorf("expected drain timeout 5s, got %v", c.DrainTimeout())
+	}
+	if err = c.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if err = <-result; err != nil {
+		t.Fatal(err)
+	}
+	for _, source := range []string{":0;ReadTimeout=-1", ":0;IdleTimeout=x", ":0;MaxHeaderBytes=-2"} {
+		if _, err = NewHttpConnector(source); err == nil {
+			t.Errorf("expected error for %s", source)
+		}
+	}
+}
@@ -1,56 +1,59 @@
 package connector
 
 import (
+	"net"
 	"net/http"
-	"strings"
 )
 
 // Https连接器
 type HttpsConnector struct {
 	HttpConnector
-	cert string
-	key  string
 }
 
 // NewHttpsConnector 创建Http连接器
-//  source:格式如: 127.0.0.1:8080;Cert=path/to/cert;Key=path/to/key
+//  source:格式如: 127.0.0.1:8080;Cert=path/to/cert;Key=path/to/key;DrainTimeout=30
+//   Cert,Key:默认证书和私钥路径,设置了SNI时可选
+//   SNI:根据主机名选择的证书,格式如: example.com|a.crt|a.key,*.example.org|b.crt|b.key
+//   ClientCA:客户端CA证书(PEM)路径,设置后验证客户端证书
+//   ClientAuth:客户端证书验证方式,可以为none,request,optional,require,设置了ClientCA时默认为require
+//   MinVersion:最低tls版本,可以为1.0,1.1,1.2,1.3,默认为1.2
+//   CipherSuites:允许的加密套件名称,以逗号分隔,可选
+//   证书和客户端CA会在收到SIGHUP时重新加载,其他可选参数与http连接器相同
 func NewHttpsConnector(source string) (Connector, error) {
-	var sources = strings.Split(source, ";")
-	if len(sources) <= 0 {
-		return nil, ErrorParamNotFound.Format("ip地址").Error()
-	}
-	var addr = sources[0]
-	var info = make(map[string]string, 2)
-	for i := 1; i < len(sources); i++ {
-		var kv = strings.Split(sources[i], "=")
-		if len(kv) == 2 {
-			info[strings.ToLower(strings.TrimSpace(kv[0]))] = strings.TrimSpace(kv[1])
-		}
-	}
+	var addr, info = parseSource(source)
 	var c = new(HttpsConnector)
 	c.addr = addr
-	var ok bool = false
-	c.cert, ok = info["cert"]
-	if !ok {
+	var err error
+	c.tlsConfig, err = parseTls(info)
+	if err != nil {
+		return nil, err
+	}
+	if c.tlsConfig == nil {
 		return nil, ErrorParamNotFound.Format("Cert").Error()
 	}
-	c.key = info["key"]
-	if !ok {
-		return nil, ErrorParamNotFound.Format("Key").Error()
+	err = c.parseInfo(info)
+	if err != nil {
+		return nil, err
 	}
 	return c, nil
 }
 
 // Run 运行(接受连接并进行处理,阻塞)
 func (this *HttpsConnector) Run() error {
-	if this.dispatcher == nil {
-		panic(ErrorInvalidDispatcher.Format("https"))
-	}
-	this.server = &http.Server{Addr: this.addr, Handler: &HttpHandler{this.dispatcher}}
-	return this.server.ListenAndServeTLS(this.cert, this.key)
+	return this.serve("https", func(server *http.Server) error {
+		var addr = this.addr
+		if addr == "" {
+			addr = ":https"
+		}
+		var l, err = net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		return this.serveListener(server, l)
+	})
 }
 
-// Stop 停止运行
+// Stop 停止运行,不再接受新的连接,并等待正在处理的请求完成
 func (this *HttpsConnector) Stop() error {
-	return ErrorFailToStop.Format("https").Error()
+	return this.stop("https")
 }
@@ -3,4 +3,6 @@ package connector
 func init() {
 	Register("http", NewHttpConnector)
 	Register("https", NewHttpsConnector)
+	Register("unix", NewUnixConnector)
+	Register("fd", NewFdConnector)
 }
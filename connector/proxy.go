@@ -0,0 +1,186 @@
+package connector
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 默认的PROXY协议头读取超时时间
+const DefaultProxyHeaderTimeout = 5 * time.Second
+
+// PROXY协议v2签名
+var proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// PROXY协议监听器,从每个连接开头读取PROXY协议(v1或v2)头并使用其中的地址作为连接的远程地址
+//  必须保证只有代理服务器能够连接该监听器,否则客户端可以伪造地址
+type ProxyListener struct {
+	net.Listener
+	HeaderTimeout time.Duration //读取PROXY协议头的超时时间
+}
+
+// NewProxyListener 创建PROXY协议监听器
+func NewProxyListener(l net.Listener) *ProxyListener {
+	return &ProxyListener{l, DefaultProxyHeaderTimeout}
+}
+
+// Accept 接受连接,PROXY协议头在第一次读取或者获取地址时解析
+func (this *ProxyListener) Accept() (net.Conn, error) {
+	var conn, err = this.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &ProxyConn{Conn: conn, reader: bufio.NewReader(conn), timeout: this.HeaderTimeout}, nil
+}
+
+// PROXY协议连接
+type ProxyConn struct {
+	net.Conn
+	reader     *bufio.Reader //带缓冲的读取器
+	timeout    time.Duration //读取协议头的超时时间
+	once       sync.Once     //保证协议头只解析一次
+	err        error         //解析协议头的错误
+	remoteAddr net.Addr      //协议头中的源地址
+	localAddr  net.Addr      //协议头中的目标地址
+}
+
+// parse 解析PROXY协议头
+func (this *ProxyConn) parse() {
+	this.once.Do(func() {
+		if this.timeout > 0 {
+			this.Conn.SetReadDeadline(time.Now().Add(this.timeout))
+			defer this.Conn.SetReadDeadline(time.Time{})
+		}
+		var sig, err = this.reader.Peek(len(proxyV2Signature))
+		if err == nil && bytes.Equal(sig, proxyV2Signature) {
+			this.err = this.parseV2()
+			return
+		}
+		sig, err = this.reader.Peek(6)
+		if err == nil && string(sig) == "PROXY " {
+			this.err = this.parseV1()
+			return
+		}
+		this.err = ErrorInvalidProxyHeader.Format(this.Conn.RemoteAddr(), "缺少协议头").Error()
+	})
+	if this.err != nil {
+		this.Conn.Close()
+	}
+}
+
+// parseV1 解析文本格式的协议头,如: PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n
+func (this *ProxyConn) parseV1() error {
+	var line []byte
+	//协议头最长107字节
+	for len(line) < 107 {
+		var b, err = this.reader.ReadByte()
+		if err != nil {
+			return ErrorInvalidProxyHeader.Format(this.Conn.RemoteAddr(), err).Error()
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	var header = string(line)
+	if !strings.HasSuffix(header, "\r\n") {
+		return ErrorInvalidProxyHeader.Format(this.Conn.RemoteAddr(), "协议头过长").Error()
+	}
+	var fields = strings.Fields(header)
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		//使用连接的真实地址
+		return nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return ErrorInvalidProxyHeader.Format(this.Conn.RemoteAddr(), strings.TrimSpace(header)).Error()
+	}
+	var src = net.ParseIP(fields[2])
+	var dst = net.ParseIP(fields[3])
+	var srcPort, err1 = strconv.ParseUint(fields[4], 10, 16)
+	var dstPort, err2 = strconv.ParseUint(fields[5], 10, 16)
+	if src == nil || dst == nil || err1 != nil || err2 != nil {
+		return ErrorInvalidProxyHeader.Format(this.Conn.RemoteAddr(), strings.TrimSpace(header)).Error()
+	}
+	this.remoteAddr = &net.TCPAddr{IP: src, Port: int(srcPort)}
+	this.localAddr = &net.TCPAddr{IP: dst, Port: int(dstPort)}
+	return nil
+}
+
+// parseV2 解析二进制格式的协议头
+func (this *ProxyConn) parseV2() error {
+	var header = make([]byte, 16)
+	var _, err = io.ReadFull(this.reader, header)
+	if err != nil {
+		return ErrorInvalidProxyHeader.Format(this.Conn.RemoteAddr(), err).Error()
+	}
+	var version, command = header[12] >> 4, header[12] & 0x0F
+	var family = header[13]
+	var length = binary.BigEndian.Uint16(header[14:16])
+	if version != 2 || command > 1 {
+		return ErrorInvalidProxyHeader.Format(this.Conn.RemoteAddr(), "无效的版本或命令").Error()
+	}
+	var data = make([]byte, length)
+	_, err = io.ReadFull(this.reader, data)
+	if err != nil {
+		return ErrorInvalidProxyHeader.Format(this.Conn.RemoteAddr(), err).Error()
+	}
+	if command == 0 {
+		//LOCAL命令,使用连接的真实地址
+		return nil
+	}
+	var size int
+	switch family >> 4 {
+	case 1:
+		size = net.IPv4len
+	case 2:
+		size = net.IPv6len
+	default:
+		//不支持的地址族,使用连接的真实地址
+		return nil
+	}
+	if len(data) < size*2+4 {
+		return ErrorInvalidProxyHeader.Format(this.Conn.RemoteAddr(), "地址长度不足").Error()
+	}
+	this.remoteAddr = &net.TCPAddr{
+		IP:   net.IP(data[:size]),
+		Port: int(binary.BigEndian.Uint16(data[size*2:])),
+	}
+	this.localAddr = &net.TCPAddr{
+		IP:   net.IP(data[size : size*2]),
+		Port: int(binary.BigEndian.Uint16(data[size*2+2:])),
+	}
+	return nil
+}
+
+// Read 读取数据
+func (this *ProxyConn) Read(b []byte) (int, error) {
+	this.parse()
+	if this.err != nil {
+		return 0, this.err
+	}
+	return this.reader.Read(b)
+}
+
+// RemoteAddr 返回协议头中的源地址
+func (this *ProxyConn) RemoteAddr() net.Addr {
+	this.parse()
+	if this.remoteAddr != nil {
+		return this.remoteAddr
+	}
+	return this.Conn.RemoteAddr()
+}
+
+// LocalAddr 返回协议头中的目标地址
+func (this *ProxyConn) LocalAddr() net.Addr {
+	this.parse()
+	if this.localAddr != nil {
+		return this.localAddr
+	}
+	return this.Conn.LocalAddr()
+}
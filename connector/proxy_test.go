@@ -0,0 +1,91 @@
+package connector
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+// testProxyConn 通过PROXY协议监听器发送header和body,返回服务端看到的地址和数据
+func testProxyConn(t *testing.T, header []byte, body string) (string, string, error) {
+	var l, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	var pl = NewProxyListener(l)
+	pl.HeaderTimeout = time.Second
+	go func() {
+		var conn, err = net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			return
+		}
+		conn.Write(append(header, body...))
+		conn.Close()
+	}()
+	conn, err := pl.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	var addr = conn.RemoteAddr().String()
+	data, err := ioutil.ReadAll(conn)
+	return addr, string(data), err
+}
+
+func TestProxyProtocolV1(t *testing.T) {
+	var addr, data, err = testProxyConn(t, []byte("PROXY TCP4 192.0.2.1 198.51.100.1 56324 443\r\n"), "GET / HTTP/1.1\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != "192.0.2.1:56324" {
+		t.Errorf("expected 192.0.2.1:56324, got %s", addr)
+	}
+	if data != "GET / HTTP/1.1\r\n" {
+		t.Errorf("unexpected data %q", data)
+	}
+	addr, _, err = testProxyConn(t, []byte("PROXY TCP6 2001:db8::1 2001:db8::2 1000 443\r\n"), "")
+	if err != nil || addr != "[2001:db8::1]:1000" {
+		t.Errorf("expected [2001:db8::1]:1000, got %s (%v)", addr, err)
+	}
+	addr, _, err = testProxyConn(t, []byte("PROXY UNKNOWN\r\n"), "")
+	if err != nil || addr == "" {
+		t.Errorf("UNKNOWN should use the real address, got %s (%v)", addr, err)
+	}
+}
+
+func TestProxyProtocolV2(t *testing.T) {
+	var header = append([]byte{}, proxyV2Signature...)
+	header = append(header, 0x21, 0x11, 0, 12+3)
+	header = append(header, 192, 0, 2, 1, 198, 51, 100, 1)
+	header = binary.BigEndian.AppendUint16(header, 56324)
+	header = binary.BigEndian.AppendUint16(header, 443)
+	//TLV
+	header = append(header, 0x04, 0, 0)
+	var addr, data, err = testProxyConn(t, header, "body")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != "192.0.2.1:56324" {
+		t.Errorf("expected 192.0.2.1:56324, got %s", addr)
+	}
+	if data != "body" {
+		t.Errorf("unexpected data %q", data)
+	}
+}
+
+func TestProxyProtocolInvalid(t *testing.T) {
+	var headers = []string{
+		"GET / HTTP/1.1\r\n",
+		"PROXY TCP4 192.0.2.1\r\n",
+		"PROXY TCP4 192.0.2.1 198.51.100.1 99999 443\r\n",
+	}
+	for _, header := range headers {
+		var _, _, err = testProxyConn(t, []byte(header), "")
+		if err == nil {
+			t.Errorf("%q: expected error", header)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package connector
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// tls协议版本
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// 客户端证书验证方式
+var tlsClientAuths = map[string]tls.ClientAuthType{
+	"none":     tls.NoClientCert,
+	"request":  tls.RequestClientCert,
+	"optional": tls.VerifyClientCertIfGiven,
+	"require":  tls.RequireAndVerifyClientCert,
+}
+
+// 证书对
+type certPair struct {
+	host string //证书对应的主机名,可以为*.example.com形式的通配符,为空表示默认证书
+	cert string //证书路径
+	key  string //私钥路径
+}
+
+// tls设置,证书和客户端CA可以在运行时重新加载
+type tlsStore struct {
+	pairs        []certPair                  //证书对,第一个为默认证书(可能为空主机名)
+	clientCA     string                      //客户端CA证书路径
+	clientAuth   tls.ClientAuthType          //客户端证书验证方式
+	minVersion   uint16                      //最低tls版本
+	cipherSuites []uint16                    //加密套件
+	mu           sync.RWMutex                //保护以下字段
+	certs        map[string]*tls.Certificate //主机名->证书
+	defaultCert  *tls.Certificate            //默认证书
+	config       *tls.Config                 //当前使用的tls设置
+	reloadErr    error                       //最近一次重新加载的错误,成功时为nil
+	handler      func(err error)             //重新加载失败时的回调
+}
+
+// parseTls 解析source中的tls参数,不存在Cert和SNI参数时返回nil
+//  Cert,Key:默认证书和私钥路径
+//  SNI:根据主机名选择的证书,格式如: example.com|a.crt|a.key,*.example.org|b.crt|b.key
+//  ClientCA:客户端CA证书(PEM)路径,设置后验证客户端证书
+//  ClientAuth:客户端证书验证方式,可以为none,request,optional,require,设置了ClientCA时默认为require
+//  MinVersion:最低tls版本,可以为1.0,1.1,1.2,1.3,默认为1.2
+//  CipherSuites:允许的加密套件名称,以逗号分隔,默认使用go的默认设置(tls1.3的加密套件不可配置)
+func parseTls(info map[string]string) (*tlsStore, error) {
+	var cert, hasCert = info["cert"]
+	var sni, hasSNI = info["sni"]
+	if !hasCert && !hasSNI {
+		return nil, nil
+	}
+	var store = new(tlsStore)
+	if hasCert && cert != "" {
+		var key, ok = info["key"]
+		if !ok {
+			return nil, ErrorParamNotFound.Format("Key").Error()
+		}
+		store.pairs = append(store.pairs, certPair{"", cert, key})
+	}
+	if hasSNI && sni != "" {
+		for _, s := range strings.Split(sni, ",") {
+			var parts = strings.Split(strings.TrimSpace(s), "|")
+			if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+				return nil, ErrorInvalidParam.Format("SNI", s).Error()
+			}
+			store.pairs = append(store.pairs, certPair{strings.ToLower(parts[0]), parts[1], parts[2]})
+		}
+	}
+	if len(store.pairs) <= 0 {
+		return nil, ErrorParamNotFound.Format("Cert").Error()
+	}
+	store.clientCA = info["clientca"]
+	store.clientAuth = tls.NoClientCert
+	if store.clientCA != "" {
+		store.clientAuth = tls.RequireAndVerifyClientCert
+	}
+	var v, ok = info["clientauth"]
+	if ok {
+		store.clientAuth, ok = tlsClientAuths[strings.ToLower(v)]
+		if !ok {
+			return nil, ErrorInvalidParam.Format("ClientAuth", v).Error()
+		}
+		if store.clientAuth >= tls.VerifyClientCertIfGiven && store.clientCA == "" {
+			return nil, ErrorParamNotFound.Format("ClientCA").Error()
+		}
+	}
+	store.minVersion = tls.VersionTLS12
+	v, ok = info["minversion"]
+	if ok {
+		store.minVersion, ok = tlsVersions[v]
+		if !ok {
+			return nil, ErrorInvalidParam.Format("MinVersion", v).Error()
+		}
+	}
+	v, ok = info["ciphersuites"]
+	if ok && v != "" {
+		var suites = make(map[string]uint16)
+		for _, s := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+			suites[s.Name] = s.ID
+		}
+		for _, name := range strings.Split(v, ",") {
+			var id, ok = suites[strings.TrimSpace(name)]
+			if !ok {
+				return nil, ErrorInvalidParam.Format("CipherSuites", name).Error()
+			}
+			store.cipherSuites = append(store.cipherSuites, id)
+		}
+	}
+	var err = store.Load()
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Load 加载全部证书和客户端CA,加载失败时保留原有设置
+func (this *tlsStore) Load() error {
+	var certs = make(map[string]*tls.Certificate)
+	var defaultCert *tls.Certificate
+	for _, p := range this.pairs {
+		var cert, err = tls.LoadX509KeyPair(p.cert, p.key)
+		if err != nil {
+			return ErrorInvalidCert.Format(p.cert, err).Error()
+		}
+		if p.host == "" || defaultCert == nil {
+			defaultCert = &cert
+		}
+		if p.host != "" {
+			certs[p.host] = &cert
+		}
+	}
+	var config = &tls.Config{
+		GetCertificate: this.certificate,
+		MinVersion:     this.minVersion,
+		CipherSuites:   this.cipherSuites,
+		ClientAuth:     this.clientAuth,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+	if this.clientCA != "" {
+		var data, err = ioutil.ReadFile(this.clientCA)
+		if err != nil {
+			return ErrorInvalidCert.Format(this.clientCA, err).Error()
+		}
+		var pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return ErrorInvalidCert.Format(this.clientCA, "没有有效的证书").Error()
+		}
+		config.ClientCAs = pool
+	}
+	this.mu.Lock()
+	this.certs = certs
+	this.defaultCert = defaultCert
+	this.config = config
+	this.mu.Unlock()
+	return nil
+}
+
+// reload 重新加载证书并记录结果,加载失败时调用回调
+func (this *tlsStore) reload() error {
+	var err = this.Load()
+	this.mu.Lock()
+	this.reloadErr = err
+	var handler = this.handler
+	this.mu.Unlock()
+	if err != nil && handler != nil {
+		handler(err)
+	}
+	return err
+}
+
+// ReloadError 返回最近一次重新加载的错误
+func (this *tlsStore) ReloadError() error {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	return this.reloadErr
+}
+
+// SetReloadHandler 设置重新加载失败时的回调
+func (this *tlsStore) SetReloadHandler(handler func(err error)) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.handler = handler
+}
+
+// certificate 根据客户端请求的主机名选择证书
+func (this *tlsStore) certificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	var name = strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+	if name != "" {
+		var cert, ok = this.certs[name]
+		if ok {
+			return cert, nil
+		}
+		//匹配通配符证书
+		var i = strings.Index(name, ".")
+		if i > 0 {
+			cert, ok = this.certs["*"+name[i:]]
+			if ok {
+				return cert, nil
+			}
+		}
+	}
+	return this.defaultCert, nil
+}
+
+// Config 返回http服务使用的tls设置,每个连接都使用最近一次加载的设置
+func (this *tlsStore) Config() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			this.mu.RLock()
+			defer this.mu.RUnlock()
+			return this.config, nil
+		},
+	}
+}
+
+// watch 收到SIGHUP时重新加载证书,直到done被关闭
+func (this *tlsStore) watch(done <-chan struct{}) {
+	var c = make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(c)
+		for {
+			select {
+			case <-c:
+				this.reload()
+			case <-done:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,214 @@
+package connector
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCert 生成证书并写入dir,返回证书和私钥路径
+func testCert(t *testing.T, dir string, name string, serial int64, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, hosts ...string) (string, string, *x509.Certificate, *ecdsa.PrivateKey) {
+	var key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var template = &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     hosts,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent = template
+		parentKey = key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var certPath = filepath.Join(dir, name+".crt")
+	var keyPath = filepath.Join(dir, name+".key")
+	ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	return certPath, keyPath, cert, key
+}
+
+func TestTlsSNIAndClientCert(t *testing.T) {
+	var dir, err = ioutil.TempDir("", "tinygo-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var caPath, _, ca, caKey = testCert(t, dir, "ca", 1, nil, nil)
+	var defCert, defKey, _, _ = testCert(t, dir, "default", 2, ca, caKey, "localhost")
+	var aCert, aKey, _, _ = testCert(t, dir, "a", 3, ca, caKey, "a.example.com")
+	var bCert, bKey, _, _ = testCert(t, dir, "b", 4, ca, caKey, "*.b.example.com")
+	var clientCert, clientKey, _, _ = testCert(t, dir, "client", 5, ca, caKey)
+
+	_, info := parseSource("127.0.0.1:0;Cert=" + defCert + ";Key=" + defKey +
+		";SNI=a.example.com|" + aCert + "|" + aKey + ",*.b.example.com|" + bCert + "|" + bKey +
+		";ClientCA=" + caPath + ";ClientAuth=optional;MinVersion=1.2")
+	store, err := parseTls(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var server = &http.Server{
+		TLSConfig: store.Config(),
+		Handler: http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if len(r.TLS.VerifiedChains) > 0 {
+				rw.Write([]byte(r.TLS.VerifiedChains[0][0].Subject.CommonName))
+			}
+		}),
+	}
+	go server.ServeTLS(l, "", "")
+	defer server.Close()
+
+	var pool = x509.NewCertPool()
+	pool.AddCert(ca)
+	var request = func(host string, certs ...tls.Certificate) (int64, string, error) {
+		var client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      pool,
+			ServerName:   host,
+			Certificates: certs,
+		}}}
+		var resp, err = client.Get("https://" + l.Addr().String())
+		if err != nil {
+			return 0, "", err
+		}
+		defer resp.Body.Close()
+		var body, _ = ioutil.ReadAll(resp.Body)
+		return resp.TLS.PeerCertificates[0].SerialNumber.Int64(), string(body), nil
+	}
+	var cases = []struct {
+		host   string
+		serial int64
+	}{
+		{"localhost", 2},
+		{"a.example.com", 3},
+		{"x.b.example.com", 4},
+	}
+	for _, c := range cases {
+		var serial, _, err = request(c.host)
+		if err != nil {
+			t.Fatalf("%s: %s", c.host, err)
+		}
+		if serial != c.serial {
+			t.Errorf("%s: expected certificate %d, got %d", c.host, c.serial, serial)
+		}
+	}
+
+	//客户端证书
+	client, err := tls.LoadX509KeyPair(clientCert, clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, name, err := request("localhost", client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "client" {
+		t.Errorf("expected verified client certificate, got %q", name)
+	}
+	var _, _, other, otherKey = testCert(t, dir, "other", 6, nil, nil)
+	var untrusted = tls.Certificate{Certificate: [][]byte{other.Raw}, PrivateKey: otherKey}
+	_, name, err = request("localhost", untrusted)
+	if err == nil && name != "" {
+		t.Errorf("untrusted client certificate should not be verified, got %q", name)
+	}
+
+	//重新加载证书
+	testCert(t, dir, "default", 7, ca, caKey, "localhost")
+	err = store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, _, err := request("localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serial != 7 {
+		t.Errorf("expected reloaded certificate 7, got %d", serial)
+	}
+
+	//重新加载失败时调用回调并保留原有证书
+	var reloadErr error
+	store.SetReloadHandler(func(err error) {
+		reloadErr = err
+	})
+	ioutil.WriteFile(defCert, []byte("invalid"), 0600)
+	if err = store.reload(); err == nil {
+		t.Fatal("expected reload error for an invalid certificate")
+	}
+	if reloadErr != err || store.ReloadError() != err {
+		t.Errorf("reload error was not reported: %v, %v", reloadErr, store.ReloadError())
+	}
+	serial, _, err = request("localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serial != 7 {
+		t.Errorf("expected previous certificate 7 after failed reload, got %d", serial)
+	}
+	testCert(t, dir, "default", 8, ca, caKey, "localhost")
+	if err = store.reload(); err != nil || store.ReloadError() != nil {
+		t.Errorf("expected successful reload, got %v", err)
+	}
+}
+
+func TestTlsInvalidParams(t *testing.T) {
+	var sources = []string{
+		":443;Cert=a.crt",
+		":443;SNI=example.com|a.crt",
+		":443;SNI=example.com:a.crt:a.key",
+		":443;Cert=a.crt;Key=a.key;ClientAuth=require",
+		":443;Cert=a.crt;Key=a.key;ClientAuth=always;ClientCA=ca.crt",
+		":443;Cert=a.crt;Key=a.key;MinVersion=2.0",
+		":443;Cert=a.crt;Key=a.key;CipherSuites=TLS_UNKNOWN",
+		":443;Cert=a.crt;Key=a.key",
+	}
+	for _, source := range sources {
+		var _, err = NewHttpsConnector(source)
+		if err == nil {
+			t.Errorf("%s: expected error", source)
+		}
+	}
+	var _, err = NewHttpsConnector(":443")
+	if err == nil {
+		t.Error("https connector without certificate should fail")
+	}
+	//路径中可以包含冒号(例如Windows路径)
+	_, err = NewHttpsConnector(`:443;SNI=example.com|C:\keys\a.crt|C:\keys\a.key`)
+	if err == nil || !strings.Contains(err.Error(), `C:\keys\a.crt`) {
+		t.Errorf("expected certificate load error for windows path, got %v", err)
+	}
+}
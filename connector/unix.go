@@ -0,0 +1,73 @@
+package connector
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// Unix域套接字连接器
+type UnixConnector struct {
+	HttpConnector
+	mode os.FileMode //套接字文件权限
+}
+
+// NewUnixConnector 创建Unix域套接字连接器
+//  source:格式如: /run/app.sock;Mode=0660;DrainTimeout=30
+//   Mode:套接字文件权限(八进制),可选,默认为0660
+//   Cert,Key,SNI:证书设置,可选,设置后使用https,tls参数与https连接器相同
+//   其他可选参数与http连接器相同
+func NewUnixConnector(source string) (Connector, error) {
+	var addr, info = parseSource(source)
+	if addr == "" {
+		return nil, ErrorParamNotFound.Format("套接字路径").Error()
+	}
+	var c = new(UnixConnector)
+	c.addr = addr
+	c.mode = 0660
+	var v, ok = info["mode"]
+	if ok {
+		var mode, err = strconv.ParseUint(v, 8, 32)
+		if err != nil {
+			return nil, ErrorInvalidParam.Format("Mode", v).Error()
+		}
+		c.mode = os.FileMode(mode)
+	}
+	var err error
+	c.tlsConfig, err = parseTls(info)
+	if err != nil {
+		return nil, err
+	}
+	err = c.parseInfo(info)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run 运行(接受连接并进行处理,阻塞)
+func (this *UnixConnector) Run() error {
+	return this.serve("unix", func(server *http.Server) error {
+		//清理上次运行遗留的套接字文件
+		var info, err = os.Lstat(this.addr)
+		if err == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(this.addr)
+		}
+		l, err := net.Listen("unix", this.addr)
+		if err != nil {
+			return err
+		}
+		err = os.Chmod(this.addr, this.mode)
+		if err != nil {
+			l.Close()
+			return err
+		}
+		return this.serveListener(server, l)
+	})
+}
+
+// Stop 停止运行,不再接受新的连接,并等待正在处理的请求完成
+func (this *UnixConnector) Stop() error {
+	return this.stop("unix")
+}
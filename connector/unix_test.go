@@ -0,0 +1,107 @@
+package connector
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitFor 等待f返回true,超时返回false
+func waitFor(f func() bool) bool {
+	for i := 0; i < 200; i++ {
+		if f() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestUnixConnector(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "app.sock")
+	//遗留的套接字文件
+	var stale, err = net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+	conn, err := NewUnixConnector(path + ";Mode=0600")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDispatcher(&testDispatcher{func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("unix"))
+	}})
+	var result = make(chan error, 1)
+	go func() {
+		result <- conn.Run()
+	}()
+	var client = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return net.Dial("unix", path)
+		},
+	}}
+	var body string
+	if !waitFor(func() bool {
+		var resp, err = client.Get("http://unix/")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		var data, _ = ioutil.ReadAll(resp.Body)
+		body = string(data)
+		return true
+	}) {
+		t.Fatal("unix connector did not replace the stale socket")
+	}
+	if body != "unix" {
+		t.Errorf("unexpected body %q", body)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+	if err = conn.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if err = <-result; err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Error("socket file was not removed after Stop")
+	}
+}
+
+func TestUnixConnectorKeepsRegularFile(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "data")
+	var err = ioutil.WriteFile(path, []byte("data"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := NewUnixConnector(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDispatcher(&testDispatcher{func(w http.ResponseWriter, r *http.Request) {}})
+	if err = conn.Run(); err == nil {
+		t.Error("expected listen error for a regular file")
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil || string(data) != "data" {
+		t.Error("regular file was removed or changed")
+	}
+	for _, source := range []string{"", "/tmp/x.sock;Mode=999"} {
+		if _, err = NewUnixConnector(source); err == nil {
+			t.Errorf("expected error for %q", source)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package tinygo
+
+import (
+	"strings"
+	"sync"
+)
+
+// App运行状态
+type appState struct {
+	app     App           //App
+	deps    []*appState   //依赖的App
+	err     error         //依赖关系错误
+	running bool          //是否已经开始运行
+	ready   chan struct{} //App就绪后关闭
+	failed  chan struct{} //App无法就绪时关闭
+	settled sync.Once     //保证ready和failed只关闭一个
+}
+
+// newAppState 创建App运行状态
+func newAppState(app App) *appState {
+	return &appState{
+		app:    app,
+		ready:  make(chan struct{}),
+		failed: make(chan struct{}),
+	}
+}
+
+// settle 设置App是否就绪,只有第一次设置有效
+func (this *appState) settle(ready bool) {
+	this.settled.Do(func() {
+		if ready {
+			close(this.ready)
+		} else {
+			close(this.failed)
+		}
+	})
+}
+
+// failedDependency 返回已经无法就绪的依赖App
+func (this *appState) failedDependency() (*appState, bool) {
+	for _, dep := range this.deps {
+		select {
+		case <-dep.failed:
+			return dep, true
+		default:
+		}
+	}
+	return nil, false
+}
+
+// SetDependencies 设置名称为name的App依赖的App名称列表,与DependentApp返回的依赖合并
+func (this *Manager) SetDependencies(name string, deps ...string) {
+	this.lazyInit()
+	this.dependencies[name] = deps
+}
+
+// Dependencies 返回app依赖的App名称列表
+func (this *Manager) Dependencies(app App) []string {
+	var deps = append([]string{}, this.dependencies[app.Name()]...)
+	var d, ok = app.(DependentApp)
+	if ok {
+		deps = append(deps, d.Dependencies()...)
+	}
+	return deps
+}
+
+// resolve 解析全部App的依赖关系
+//  return:按照依赖顺序(被依赖的App在前)排列的App运行状态,重复名称的App直接报告InitFailed并且不会返回
+func (this *Manager) resolve() []*appState {
+	var states = make([]*appState, 0, len(this.Apps))
+	var names = make(map[string]*appState, len(this.Apps))
+	for _, app := range this.Apps {
+		var _, exist = names[app.Name()]
+		if exist {
+			if this.Event != nil {
+				this.Event.InitFailed(this, app, ErrorDuplicateApp.Format(app.Name()).Error())
+			}
+			continue
+		}
+		var s = newAppState(app)
+		names[app.Name()] = s
+		states = append(states, s)
+	}
+	for _, s := range states {
+		for _, name := range this.Dependencies(s.app) {
+			var dep, ok = names[name]
+			if !ok {
+				if s.err == nil {
+					s.err = ErrorDependencyNotFound.Format(s.app.Name(), name).Error()
+				}
+				continue
+			}
+			s.deps = append(s.deps, dep)
+		}
+	}
+	//深度优先遍历,生成拓扑顺序并检查循环依赖
+	var order = make([]*appState, 0, len(states))
+	var visited = make(map[*appState]bool, len(states))
+	var stack = make([]*appState, 0)
+	var visit func(s *appState)
+	visit = func(s *appState) {
+		var done, ok = visited[s]
+		if ok {
+			if !done {
+				//s在遍历栈中,栈中从s开始的App构成环
+				var start = len(stack) - 1
+				for stack[start] != s {
+					start--
+				}
+				var path = make([]string, 0, len(stack)-start+1)
+				for _, c := range stack[start:] {
+					path = append(path, c.app.Name())
+				}
+				path = append(path, s.app.Name())
+				for _, c := range stack[start:] {
+					if c.err == nil {
+						c.err = ErrorCyclicDependency.Format(c.app.Name(), strings.Join(path, "->")).Error()
+					}
+				}
+			}
+			return
+		}
+		visited[s] = false
+		stack = append(stack, s)
+		for _, dep := range s.deps {
+			visit(dep)
+		}
+		stack = stack[:len(stack)-1]
+		visited[s] = true
+		order = append(order, s)
+	}
+	for _, s := range states {
+		visit(s)
+	}
+	return order
+}
+
+// await 等待s的全部依赖就绪
+//  return:依赖全部就绪则返回true,依赖无法就绪或者Manager正在停止则返回false
+func (this *Manager) await(s *appState) bool {
+	for _, dep := range s.deps {
+		select {
+		case <-dep.ready:
+		case <-dep.failed:
+			if this.Event != nil {
+				this.Event.InitFailed(this, s.app, ErrorDependencyFailed.Format(s.app.Name(), dep.app.Name()).Error())
+			}
+			s.settle(false)
+			return false
+		case <-this.stop:
+			s.settle(false)
+			return false
+		}
+	}
+	return true
+}
@@ -25,7 +25,11 @@ func (this Error) String() string {
 
 // 错误码
 const (
-//ErrorConfigNotCorrect     Error = "ErrorConfigNotCorrect(T10010):配置文件中%s的%s不正确"
-//ErrorConnectorCreateFail  Error = "ErrorConnectorCreateFail(T10020):连接器(%s)创建失败,%s"
-//ErrorRootRouterCreateFail Error = "ErrorRootRouterCreateFail(T10030):根路由(%s)创建失败,%s"
+	//ErrorConfigNotCorrect     Error = "ErrorConfigNotCorrect(T10010):配置文件中%s的%s不正确"
+	//ErrorConnectorCreateFail  Error = "ErrorConnectorCreateFail(T10020):连接器(%s)创建失败,%s"
+	//ErrorRootRouterCreateFail Error = "ErrorRootRouterCreateFail(T10030):根路由(%s)创建失败,%s"
+	ErrorDuplicateApp       Error = "ErrorDuplicateApp(T10040):App名称(%s)重复"
+	ErrorDependencyNotFound Error = "ErrorDependencyNotFound(T10041):App(%s)依赖的App(%s)不存在"
+	ErrorCyclicDependency   Error = "ErrorCyclicDependency(T10042):App(%s)存在循环依赖(%s)"
+	ErrorDependencyFailed   Error = "ErrorDependencyFailed(T10043):App(%s)依赖的App(%s)未能就绪"
 )
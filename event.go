@@ -2,6 +2,7 @@ package tinygo
 
 import (
 	"fmt"
+	"time"
 )
 
 // 管理器事件
@@ -20,6 +21,14 @@ type ManagerEvent interface {
 	RunPaniced(manager *Manager, app App, info interface{})
 	// RunFinished 在每个App运行完毕的时候触发
 	RunFinished(manager *Manager, app App)
+	// Restarting 在App根据重启策略等待重启前触发,restarts为重启策略时间窗口内的重启次数(包括本次),delay为重启前的等待时间
+	Restarting(manager *Manager, app App, restarts int, delay time.Duration)
+	// Stopping 在每个App停止前触发
+	Stopping(manager *Manager, app App)
+	// Stopped 在每个App成功停止后触发
+	Stopped(manager *Manager, app App)
+	// StopFailed 在每个App停止出错的时候触发
+	StopFailed(manager *Manager, app App, err error)
 	// Ended 在Manager停止运行时触发
 	Ended(manager *Manager)
 }
@@ -63,6 +72,26 @@ func (this *DefaultManagerEvent) RunFinished(manager *Manager, app App) {
 
 }
 
+// Restarting 在App根据重启策略等待重启前触发
+func (this *DefaultManagerEvent) Restarting(manager *Manager, app App, restarts int, delay time.Duration) {
+	fmt.Println(app.Name(), "App 将在", delay, "后第", restarts, "次重启")
+}
+
+// Stopping 在每个App停止前触发
+func (this *DefaultManagerEvent) Stopping(manager *Manager, app App) {
+
+}
+
+// Stopped 在每个App成功停止后触发
+func (this *DefaultManagerEvent) Stopped(manager *Manager, app App) {
+
+}
+
+// StopFailed 在每个App停止出错的时候触发
+func (this *DefaultManagerEvent) StopFailed(manager *Manager, app App, err error) {
+	fmt.Println(app.Name(), "App 停止错误", err)
+}
+
 // Ended 在Manager停止运行时触发
 func (this *DefaultManagerEvent) Ended(manager *Manager) {
 
@@ -41,8 +41,10 @@ func (this *FileWriter) Write(log string) {
 
 // Close 关闭写入器
 func (this *FileWriter) Close() {
-	this.writer.Flush()
-	this.file.Close()
+	if this.file != nil {
+		this.writer.Flush()
+		this.file.Close()
+	}
 }
 
 // createLogFile 创建日志文件
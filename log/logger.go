@@ -142,6 +142,10 @@ func (this *SimpleLogger) Closed() bool {
 // Close 关闭日志 关闭后无法再使用
 func (this *SimpleLogger) Close() {
 	if !this.Closed() {
+		if this.async {
+			//将尚未输出的异步日志全部输出
+			this.SetAsync(false)
+		}
 		this.closed = true
 		this.logWriter.Close()
 	}
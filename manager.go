@@ -1,16 +1,54 @@
 package tinygo
 
-import "sync"
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
 
-// 管理器
+// 默认的停止期限,所有App必须在该期限内停止
+const DefaultShutdownTimeout = 60 * time.Second
+
+// 管理器,零值Manager{}可以直接使用,停止期限为DefaultShutdownTimeout
 type Manager struct {
-	Apps  []App
-	Event ManagerEvent
+	Apps            []App                     //App列表
+	Event           ManagerEvent              //管理器事件
+	ShutdownTimeout time.Duration             //停止全部App的总期限,为0时使用DefaultShutdownTimeout
+	stop            chan struct{}             //关闭后开始停止全部App
+	stopOnce        sync.Once                 //保证stop只关闭一次
+	dependencies    map[string][]string       //通过SetDependencies设置的依赖关系
+	policies        map[string]*RestartPolicy //通过SetRestartPolicy设置的重启策略
+	initOnce        sync.Once                 //保证零值Manager只初始化一次
 }
 
 // NewManager 创建App管理器
 func NewManager() (*Manager, error) {
-	return &Manager{make([]App, 0, 1), &DefaultManagerEvent{}}, nil
+	return &Manager{
+		Apps:            make([]App, 0, 1),
+		Event:           &DefaultManagerEvent{},
+		ShutdownTimeout: DefaultShutdownTimeout,
+		stop:            make(chan struct{}),
+		dependencies:    make(map[string][]string),
+		policies:        make(map[string]*RestartPolicy),
+	}, nil
+}
+
+// lazyInit 初始化零值Manager的停止通道和映射
+func (this *Manager) lazyInit() {
+	this.initOnce.Do(func() {
+		if this.stop == nil {
+			this.stop = make(chan struct{})
+		}
+		if this.dependencies == nil {
+			this.dependencies = make(map[string][]string)
+		}
+		if this.policies == nil {
+			this.policies = make(map[string]*RestartPolicy)
+		}
+	})
 }
 
 // AddApp 添加App
@@ -18,47 +56,194 @@ func (this *Manager) AddApp(app App) {
 	this.Apps = append(this.Apps, app)
 }
 
-// Run 运行App
+// Stop 通知Manager停止全部App,效果与收到SIGINT或SIGTERM信号相同
+func (this *Manager) Stop() {
+	this.lazyInit()
+	this.stopOnce.Do(func() {
+		close(this.stop)
+	})
+}
+
+// Run 按照依赖顺序初始化并运行App,直到全部App运行结束或者收到停止信号并停止全部App后返回
 func (this *Manager) Run() {
-	var err error
+	this.lazyInit()
 	if this.Event != nil {
 		this.Event.Started(this)
 		defer this.Event.Ended(this)
 	}
 	var w sync.WaitGroup
-	w.Add(len(this.Apps))
-	for _, app := range this.Apps {
+	var mu sync.Mutex
+	var states = this.resolve()
+	for _, s := range states {
+		var app = s.app
 		if this.Event != nil {
 			this.Event.BeforeRunning(this, app)
 		}
-		err = app.Init()
+		var err = s.err
+		if err == nil {
+			var dep, failed = s.failedDependency()
+			if failed {
+				err = ErrorDependencyFailed.Format(app.Name(), dep.app.Name()).Error()
+			} else {
+				err = app.Init()
+			}
+		}
 		if err != nil {
-			this.Event.InitFailed(this, app, err)
+			if this.Event != nil {
+				this.Event.InitFailed(this, app, err)
+			}
+			s.settle(false)
 			continue
 		}
-		go func(app App) {
-			defer func() {
-				if err := recover(); err != nil {
-					if this.Event != nil {
-						this.Event.RunPaniced(this, app, err)
-					}
-				}
-				w.Done()
-			}()
-			var err = app.Run()
-			if err != nil {
-				if this.Event != nil {
-					this.Event.RunFailed(this, app, err)
-				}
-			} else {
-				if this.Event != nil {
-					this.Event.RunFinished(this, app)
-				}
+		w.Add(1)
+		go func(s *appState) {
+			defer w.Done()
+			if !this.await(s) {
+				return
 			}
-		}(app)
+			mu.Lock()
+			if this.stopping() {
+				//Manager正在停止,不再运行新的App
+				mu.Unlock()
+				s.settle(false)
+				return
+			}
+			s.running = true
+			mu.Unlock()
+			this.run(s)
+		}(s)
 		if this.Event != nil {
 			this.Event.AferRunning(this, app)
 		}
 	}
-	w.Wait()
+	var finished = make(chan struct{})
+	go func() {
+		w.Wait()
+		close(finished)
+	}()
+	var signals = make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	select {
+	case <-finished:
+		return
+	case <-signals:
+		this.Stop()
+	case <-this.stop:
+	}
+	//按照依赖顺序的逆序停止已经开始运行的App
+	var apps = make([]App, 0, len(states))
+	mu.Lock()
+	for i := len(states) - 1; i >= 0; i-- {
+		if states[i].running {
+			apps = append(apps, states[i].app)
+		}
+	}
+	mu.Unlock()
+	this.shutdown(apps, finished)
+}
+
+// run 按照重启策略运行App并报告App的就绪状态
+func (this *Manager) run(s *appState) {
+	var app = s.app
+	var exited = make(chan struct{})
+	var r, ok = app.(ReadyApp)
+	if ok {
+		go func() {
+			select {
+			case <-r.Ready():
+				s.settle(true)
+			case <-exited:
+			}
+		}()
+	} else {
+		s.settle(true)
+	}
+	var success = false
+	defer func() {
+		close(exited)
+		//运行正常结束的App同样视为就绪
+		s.settle(success)
+	}()
+	var rs = &restarter{policy: this.RestartPolicy(app)}
+	for {
+		success = this.runOnce(app)
+		var delay, restarts, restart = rs.next(!success)
+		if !restart || this.stopping() {
+			return
+		}
+		if this.Event != nil {
+			this.Event.Restarting(this, app, restarts, delay)
+		}
+		select {
+		case <-time.After(delay):
+		case <-this.stop:
+			return
+		}
+	}
+}
+
+// runOnce 运行一次App
+//  return:App是否正常运行结束
+func (this *Manager) runOnce(app App) (success bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			if this.Event != nil {
+				this.Event.RunPaniced(this, app, err)
+			}
+			success = false
+		}
+	}()
+	var err = app.Run()
+	if err != nil {
+		if this.Event != nil {
+			this.Event.RunFailed(this, app, err)
+		}
+		return false
+	}
+	if this.Event != nil {
+		this.Event.RunFinished(this, app)
+	}
+	return true
+}
+
+// stopping 返回Manager是否正在停止
+func (this *Manager) stopping() bool {
+	select {
+	case <-this.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// shutdown 依次停止apps,并等待全部App运行结束或者超过停止期限
+func (this *Manager) shutdown(apps []App, finished <-chan struct{}) {
+	var timeout = this.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	var ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for _, a := range apps {
+		var app, ok = a.(StoppableApp)
+		if !ok {
+			continue
+		}
+		if this.Event != nil {
+			this.Event.Stopping(this, app)
+		}
+		var err = app.Stop(ctx)
+		if this.Event != nil {
+			if err != nil {
+				this.Event.StopFailed(this, app, err)
+			} else {
+				this.Event.Stopped(this, app)
+			}
+		}
+	}
+	select {
+	case <-finished:
+	case <-ctx.Done():
+	}
 }
@@ -0,0 +1,344 @@
+package tinygo
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kdada/tinygo/connector"
+	"github.com/kdada/tinygo/web"
+)
+
+// 用于测试的App
+type TestApp struct {
+	name    string
+	stopped chan struct{}
+	order   *[]string
+	mu      *sync.Mutex
+}
+
+func NewTestApp(name string, order *[]string, mu *sync.Mutex) *TestApp {
+	return &TestApp{name, make(chan struct{}), order, mu}
+}
+
+func (this *TestApp) Name() string {
+	return this.name
+}
+
+func (this *TestApp) Init() error {
+	return nil
+}
+
+func (this *TestApp) Run() error {
+	<-this.stopped
+	return nil
+}
+
+func (this *TestApp) Stop(ctx context.Context) error {
+	this.mu.Lock()
+	*this.order = append(*this.order, this.name)
+	this.mu.Unlock()
+	close(this.stopped)
+	return nil
+}
+
+// 记录事件的事件处理器
+type TestManagerEvent struct {
+	DefaultManagerEvent
+	finished int32
+	stopped  int32
+}
+
+func (this *TestManagerEvent) RunFinished(manager *Manager, app App) {
+	atomic.AddInt32(&this.finished, 1)
+}
+
+func (this *TestManagerEvent) Stopped(manager *Manager, app App) {
+	atomic.AddInt32(&this.stopped, 1)
+}
+
+func TestManagerStop(t *testing.T) {
+	var order = make([]string, 0)
+	var mu sync.Mutex
+	var m, _ = NewManager()
+	var event = new(TestManagerEvent)
+	m.Event = event
+	m.AddApp(NewTestApp("a", &order, &mu))
+	m.AddApp(NewTestApp("b", &order, &mu))
+	m.AddApp(NewTestApp("c", &order, &mu))
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		m.Stop()
+	}()
+	m.Run()
+	if len(order) != 3 || order[0] != "c" || order[1] != "b" || order[2] != "a" {
+		t.Fatal("App停止顺序错误", order)
+	}
+	if event.stopped != 3 {
+		t.Fatal("Stopped事件次数错误", event.stopped)
+	}
+	if event.finished != 3 {
+		t.Fatal("RunFinished事件次数错误", event.finished)
+	}
+}
+
+// 用于测试依赖关系的App
+type TestDependentApp struct {
+	TestApp
+	deps  []string
+	ready chan struct{}
+}
+
+func NewTestDependentApp(name string, order *[]string, mu *sync.Mutex, deps ...string) *TestDependentApp {
+	return &TestDependentApp{*NewTestApp(name, order, mu), deps, make(chan struct{})}
+}
+
+func (this *TestDependentApp) Init() error {
+	this.mu.Lock()
+	*this.order = append(*this.order, "init:"+this.name)
+	this.mu.Unlock()
+	return nil
+}
+
+func (this *TestDependentApp) Run() error {
+	this.mu.Lock()
+	*this.order = append(*this.order, "run:"+this.name)
+	this.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	close(this.ready)
+	<-this.stopped
+	return nil
+}
+
+func (this *TestDependentApp) Stop(ctx context.Context) error {
+	close(this.stopped)
+	return nil
+}
+
+func (this *TestDependentApp) Dependencies() []string {
+	return this.deps
+}
+
+func (this *TestDependentApp) Ready() <-chan struct{} {
+	return this.ready
+}
+
+// 记录初始化错误的事件处理器
+type TestInitEvent struct {
+	DefaultManagerEvent
+	mu     sync.Mutex
+	failed map[string]error
+}
+
+func (this *TestInitEvent) InitFailed(manager *Manager, app App, err error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.failed[app.Name()] = err
+}
+
+func TestManagerDependency(t *testing.T) {
+	var order = make([]string, 0)
+	var mu sync.Mutex
+	var m, _ = NewManager()
+	var event = &TestInitEvent{failed: make(map[string]error)}
+	m.Event = event
+	m.AddApp(NewTestDependentApp("api", &order, &mu, "job"))
+	m.AddApp(NewTestDependentApp("job", &order, &mu, "db"))
+	m.AddApp(NewTestDependentApp("db", &order, &mu))
+	m.AddApp(NewTestDependentApp("x", &order, &mu, "y"))
+	m.AddApp(NewTestDependentApp("y", &order, &mu, "x"))
+	m.AddApp(NewTestDependentApp("z", &order, &mu, "none"))
+	m.AddApp(NewTestDependentApp("w", &order, &mu))
+	m.SetDependencies("w", "z")
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		m.Stop()
+	}()
+	m.Run()
+	var expected = []string{"init:db", "init:job", "init:api", "run:db", "run:job", "run:api"}
+	if len(order) != len(expected) {
+		t.Fatal("App初始化和运行顺序错误", order)
+	}
+	for i, v := range expected {
+		if order[i] != v {
+			t.Fatal("App初始化和运行顺序错误", order)
+		}
+	}
+	if len(event.failed) != 4 {
+		t.Fatal("InitFailed事件错误", event.failed)
+	}
+	for _, name := range []string{"x", "y"} {
+		if !strings.Contains(event.failed[name].Error(), "T10042") {
+			t.Fatal("循环依赖检查错误", event.failed[name])
+		}
+	}
+	if !strings.Contains(event.failed["z"].Error(), "T10041") {
+		t.Fatal("依赖缺失检查错误", event.failed["z"])
+	}
+	if !strings.Contains(event.failed["w"].Error(), "T10043") {
+		t.Fatal("依赖失败检查错误", event.failed["w"])
+	}
+}
+
+// 运行出错的App
+type TestCrashApp struct {
+	name  string
+	runs  int
+	crash int
+}
+
+func (this *TestCrashApp) Name() string {
+	return this.name
+}
+
+func (this *TestCrashApp) Init() error {
+	return nil
+}
+
+func (this *TestCrashApp) Run() error {
+	this.runs++
+	if this.runs <= this.crash {
+		panic("crash")
+	}
+	return nil
+}
+
+// 记录重启的事件处理器
+type TestRestartEvent struct {
+	DefaultManagerEvent
+	mu     sync.Mutex
+	delays map[string][]time.Duration
+}
+
+func (this *TestRestartEvent) RunPaniced(manager *Manager, app App, info interface{}) {
+}
+
+func (this *TestRestartEvent) Restarting(manager *Manager, app App, restarts int, delay time.Duration) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.delays[app.Name()] = append(this.delays[app.Name()], delay)
+}
+
+func TestManagerRestart(t *testing.T) {
+	var m, _ = NewManager()
+	var event = &TestRestartEvent{delays: make(map[string][]time.Duration)}
+	m.Event = event
+	var a = &TestCrashApp{"a", 0, 2}
+	var b = &TestCrashApp{"b", 0, 100}
+	var c = &TestCrashApp{"c", 0, 1}
+	m.AddApp(a)
+	m.AddApp(b)
+	m.AddApp(c)
+	m.SetRestartPolicy("a", &RestartPolicy{RestartOnFailure, time.Millisecond, 3 * time.Millisecond, 0, time.Minute})
+	m.SetRestartPolicy("b", &RestartPolicy{RestartOnFailure, time.Millisecond, 2 * time.Millisecond, 3, time.Minute})
+	m.Run()
+	if a.runs != 3 || len(event.delays["a"]) != 2 || event.delays["a"][0] != time.Millisecond || event.delays["a"][1] != 2*time.Millisecond {
+		t.Fatal("按失败重启错误", a.runs, event.delays["a"])
+	}
+	if b.runs != 4 || len(event.delays["b"]) != 3 || event.delays["b"][2] != 2*time.Millisecond {
+		t.Fatal("最大重启次数错误", b.runs, event.delays["b"])
+	}
+	if c.runs != 1 || len(event.delays["c"]) != 0 {
+		t.Fatal("默认重启策略错误", c.runs)
+	}
+}
+
+// 第一次重启前释放被占用端口的事件处理器
+type TestListenEvent struct {
+	DefaultManagerEvent
+	blocker  net.Listener
+	restarts int32
+}
+
+func (this *TestListenEvent) RunFailed(manager *Manager, app App, err error) {
+}
+
+func (this *TestListenEvent) Restarting(manager *Manager, app App, restarts int, delay time.Duration) {
+	if atomic.AddInt32(&this.restarts, 1) == 1 {
+		this.blocker.Close()
+	}
+}
+
+func TestManagerRestartWebApp(t *testing.T) {
+	var blocker, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var addr = blocker.Addr().String()
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var freeAddr = free.Addr().String()
+	free.Close()
+	var config = web.NewHttpConfig()
+	config.App = "web"
+	config.Log = true
+	config.LogType = "console"
+	config.Session = false
+	processor, err := web.NewHttpProcessor(web.NewRootRouter(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var app = &web.WebApp{Processor: processor}
+	for _, a := range []string{addr, freeAddr} {
+		var conn, err = connector.NewConnector("http", a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		app.AddConnector(conn)
+	}
+	var m, _ = NewManager()
+	var event = &TestListenEvent{blocker: blocker}
+	m.Event = event
+	m.AddApp(app)
+	m.SetRestartPolicy("web", &RestartPolicy{RestartOnFailure, 10 * time.Millisecond, 10 * time.Millisecond, 0, time.Minute})
+	var done = make(chan struct{})
+	go func() {
+		m.Run()
+		close(done)
+	}()
+	var served = false
+	for i := 0; i < 100 && !served; i++ {
+		var resp, err = http.Get("http://" + addr + "/")
+		if err == nil {
+			resp.Body.Close()
+			served = true
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	m.Stop()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("WebApp没有停止")
+	}
+	if !served || atomic.LoadInt32(&event.restarts) < 1 {
+		t.Fatal("监听失败后WebApp没有重启", served, event.restarts)
+	}
+}
+
+func TestZeroManager(t *testing.T) {
+	var order = make([]string, 0)
+	var mu sync.Mutex
+	var m Manager
+	m.SetDependencies("b", "a")
+	m.SetRestartPolicy("a", NewRestartPolicy(RestartNever))
+	m.AddApp(NewTestApp("a", &order, &mu))
+	m.AddApp(NewTestApp("b", &order, &mu))
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		m.Stop()
+	}()
+	m.Run()
+	m.Stop()
+	if len(order) != 2 || order[0] != "b" || order[1] != "a" {
+		t.Fatal("零值Manager的停止顺序错误", order)
+	}
+}
@@ -23,29 +23,44 @@ type FieldMetadata struct {
 	Field     *reflect.StructField //字段信息
 	Kind      FieldKind            //字段解析类型
 	Validator validator.Validator  //验证器
+	Rule      string               //验证字符串
+	Message   string               //msg标签,未通过校验时的错误信息模板或者错误信息名称
 }
 
 // Set 使用vc设置object的值
 //  instance:拥有当前字段的对象的指针的反射值
+//  字段未通过校验时返回*FieldError,嵌套字段未通过校验时返回ValidationErrors
 func (this *FieldMetadata) Set(instance reflect.Value, vc ValueContainer) error {
 	if this.Kind != FieldKindIgnore {
 		var vp, exist = vc.Contains(this.Name, this.Field.Type)
+		if !exist {
+			//嵌套的结构体,切片和map
+			var nvc, ok = vc.(NestedValueContainer)
+			if ok {
+				var err error
+				vp, exist, err = GenerateNested(nvc, this.Name, this.Field.Type)
+				if err != nil {
+					return err
+				}
+			}
+		}
 		if exist {
 			var valid = true
 			if this.Validator != nil {
 				//验证器校验
 				var strs = vp.String()
 				if len(strs) <= 0 && this.Kind == FieldKindRequired {
-					return ErrorRequiredField.Format(this.Name).Error()
+					return newFieldError(this, -1, "", nil, ErrorRequiredField.Format(this.Name))
 				}
 				for i, v := range strs {
-					valid = this.Validator.Validate(v)
+					var node *validator.ExecutableFuncNode
+					valid, node = this.validate(v)
 					if !valid {
 						if this.Kind == FieldKindRequired {
 							if len(strs) == 1 {
-								return ErrorFieldNotValid.Format(this.Name).Error()
+								return newFieldError(this, i, v, node, ErrorFieldNotValid.Format(this.Name))
 							}
-							return ErrorFieldsNotValid.Format(this.Name, i).Error()
+							return newFieldError(this, i, v, node, ErrorFieldsNotValid.Format(this.Name, i))
 						}
 						break
 					}
@@ -58,16 +73,26 @@ func (this *FieldMetadata) Set(instance reflect.Value, vc ValueContainer) error
 					var fValue = reflect.ValueOf(value)
 					instance.Elem().FieldByIndex(this.Field.Index).Set(fValue)
 				} else if this.Kind == FieldKindRequired {
-					return ErrorRequiredField.Format(this.Name).Error()
+					return newFieldError(this, -1, "", nil, ErrorRequiredField.Format(this.Name))
 				}
 			}
 		} else if this.Kind == FieldKindRequired {
-			return ErrorRequiredField.Format(this.Name).Error()
+			return newFieldError(this, -1, "", nil, ErrorRequiredField.Format(this.Name))
 		}
 	}
 	return nil
 }
 
+// validate 使用验证器验证str,验证器为validator.ExtendedValidator时返回导致验证失败的函数节点
+func (this *FieldMetadata) validate(str string) (bool, *validator.ExecutableFuncNode) {
+	var ev, ok = this.Validator.(validator.ExtendedValidator)
+	if ok {
+		var node = ev.Check(str)
+		return node == nil, node
+	}
+	return this.Validator.Validate(str), nil
+}
+
 // 验证字符串提取正则
 var vldReg = regexp.MustCompile("^[?!] *?;(.*)$")
 
@@ -76,6 +101,8 @@ var vldReg = regexp.MustCompile("^[?!] *?;(.*)$")
 //    格式范例(验证函数参考validator包):
 //    `vld:"?;>0&&<10"`
 //    `?;>0&&<10`
+//  msg标签为错误信息模板或者使用validator.RegisterMessage注册的错误信息名称,格式参考validator.RegisterMessage
+//    `vld:"!;len>2" msg:"{field}至少需要3个字符"`
 func AnalyzeField(field *reflect.StructField) (*FieldMetadata, error) {
 	var tag = field.Tag.Get("vld")
 	tag = strings.TrimSpace(tag)
@@ -91,6 +118,7 @@ func AnalyzeField(field *reflect.StructField) (*FieldMetadata, error) {
 	var fMd = new(FieldMetadata)
 	fMd.Name = field.Name
 	fMd.Field = field
+	fMd.Message = field.Tag.Get("msg")
 	switch {
 	case strings.HasPrefix(tag, "!"):
 		fMd.Kind = FieldKindRequired
@@ -112,6 +140,7 @@ func AnalyzeField(field *reflect.StructField) (*FieldMetadata, error) {
 					return nil, err
 				}
 				fMd.Validator = vld
+				fMd.Rule = src
 			}
 		}
 	}
@@ -17,6 +17,13 @@ type ValueContainer interface {
 	Contains(name string, t reflect.Type) (ValueProvider, bool)
 }
 
+// 结构体值容器,能够为指定的结构体提供专用的值容器,例如使用请求体解码的结构体字段
+type StructValueContainer interface {
+	ValueContainer
+	// StructContainer 返回生成结构体s的字段时使用的值容器,不需要专用的值容器时返回nil
+	StructContainer(s reflect.Type) (ValueContainer, error)
+}
+
 // 生成器
 type Generator interface {
 	// Generate 根据vc提供的值生成相应值
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -92,3 +93,198 @@ func TestMeta(t *testing.T) {
 	}
 
 }
+
+type testStructContainer struct {
+	*DefaultValueContainer
+	fields map[string]*ValueGenerator
+}
+
+func (this *testStructContainer) StructContainer(s reflect.Type) (ValueContainer, error) {
+	var c = NewDefaultValueContainer()
+	c.NameContainer = this.fields
+	return c, nil
+}
+
+type TestBody struct {
+	Name string `vld:"!;len>2"`
+	Age  int
+}
+
+func TestStructValueContainer(t *testing.T) {
+	var vc = &testStructContainer{NewDefaultValueContainer(), map[string]*ValueGenerator{
+		"Name": {[]string{"ab"}, func() interface{} { return "ab" }},
+		"Age":  {[]string{"3"}, func() interface{} { return 3 }},
+	}}
+	var g, err = AnalyzeStruct(reflect.TypeOf(&TestBody{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.Generate(vc)
+	if err == nil {
+		t.Fatal("结构体值容器提供的字段没有进行校验")
+	}
+	vc.fields["Name"] = &ValueGenerator{[]string{"abc"}, func() interface{} { return "abc" }}
+	v, err := g.Generate(vc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body = v.(*TestBody)
+	if body.Name != "abc" || body.Age != 3 {
+		t.Fatal("结构体值容器注入错误", body)
+	}
+}
+
+type testNestedContainer struct {
+	prefix string
+	values map[string]string
+}
+
+func (this *testNestedContainer) path(name string) string {
+	if this.prefix == "" {
+		return name
+	}
+	return this.prefix + "." + name
+}
+
+func (this *testNestedContainer) Contains(name string, t reflect.Type) (ValueProvider, bool) {
+	var v, ok = this.values[this.path(name)]
+	if !ok || t.Kind() != reflect.String {
+		return nil, false
+	}
+	return &DefaultValueProvider{&ValueGenerator{[]string{v}, func() interface{} { return v }}}, true
+}
+
+func (this *testNestedContainer) Nested(name string) NestedValueContainer {
+	return &testNestedContainer{this.path(name), this.values}
+}
+
+func (this *testNestedContainer) Keys(name string) []string {
+	var prefix = this.path(name) + "."
+	var exist = make(map[string]bool)
+	var result = make([]string, 0)
+	for k := range this.values {
+		if strings.HasPrefix(k, prefix) {
+			var child = strings.Split(k[len(prefix):], ".")[0]
+			if !exist[child] {
+				exist[child] = true
+				result = append(result, child)
+			}
+		}
+	}
+	return result
+}
+
+type TestAddress struct {
+	City string `vld:"!;len>1"`
+}
+
+type TestOrder struct {
+	Address TestAddress
+	Billing *TestAddress
+	Items   []TestAddress
+	Tags    []string
+	Attrs   map[string]string
+}
+
+func TestNestedValueContainer(t *testing.T) {
+	var vc = &testNestedContainer{"", map[string]string{
+		"Address.City":  "Paris",
+		"Billing.City":  "Lyon",
+		"Items.10.City": "Rome",
+		"Items.2.City":  "Oslo",
+		"Tags.0":        "a",
+		"Tags.1":        "b",
+		"Attrs.color":   "red",
+	}}
+	var g, err = AnalyzeStruct(reflect.TypeOf(&TestOrder{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := g.Generate(vc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order = v.(*TestOrder)
+	if order.Address.City != "Paris" || order.Billing == nil || order.Billing.City != "Lyon" {
+		t.Fatal("嵌套结构体注入错误", order)
+	}
+	if len(order.Items) != 2 || order.Items[0].City != "Oslo" || order.Items[1].City != "Rome" {
+		t.Fatal("结构体切片注入错误", order.Items)
+	}
+	if len(order.Tags) != 2 || order.Tags[0] != "a" || order.Tags[1] != "b" || order.Attrs["color"] != "red" {
+		t.Fatal("切片或map注入错误", order.Tags, order.Attrs)
+	}
+	vc.values["Items.2.City"] = "O"
+	_, err = g.Generate(vc)
+	if err == nil {
+		t.Fatal("嵌套结构体的字段没有进行校验")
+	}
+}
+
+type TestReport struct {
+	Name   string `vld:"!;len>2"`
+	Age    string `vld:"!;len>0"`
+	Items  []TestAddress
+	Errors ValidationErrors
+}
+
+func TestValidationErrors(t *testing.T) {
+	var vc = &testNestedContainer{"", map[string]string{
+		"Address.City": "P",
+		"Items.0.City": "O",
+		"Items.1.City": "Oslo",
+	}}
+	var g, err = AnalyzeStruct(reflect.TypeOf(&TestOrder{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.Generate(vc)
+	var errs, ok = err.(ValidationErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatal("没有收集所有字段的校验错误", err)
+	}
+	if errs[0].Field != "Address.City" || errs[0].Rule != "len>1" || errs[0].Value != "P" || errs[1].Field != "Items.0.City" {
+		t.Fatal("字段校验错误信息错误", errs[0], errs[1])
+	}
+	vc.values["Name"] = "ab"
+	g, err = AnalyzeStruct(reflect.TypeOf(&TestReport{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := g.Generate(vc)
+	if err != nil {
+		t.Fatal("校验错误没有注入到ValidationErrors字段", err)
+	}
+	var report = v.(*TestReport)
+	if len(report.Errors) != 3 || report.Errors[1].Field != "Age" || report.Errors[1].Index != -1 || report.Errors[1].Rule != "!" {
+		t.Fatal("注入的校验错误信息错误", report.Errors)
+	}
+}
+
+type TestMessage struct {
+	Name string `vld:"!;len>2&&len<=5"`
+	Code string `vld:"!;/^[a-z]+$/" msg:"{field}只能包含小写字母:{value}"`
+}
+
+func TestValidationMessage(t *testing.T) {
+	var vc = &testNestedContainer{"", map[string]string{
+		"Name": "abcdef",
+		"Code": "AB",
+	}}
+	var g, err = AnalyzeStruct(reflect.TypeOf(&TestMessage{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.Generate(vc)
+	var errs, ok = err.(ValidationErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatal("没有收集所有字段的校验错误", err)
+	}
+	if errs[0].Rule != "len<=5" || errs[0].Message != "Name的长度必须小于或等于5" || errs[1].Message != "Code只能包含小写字母:AB" {
+		t.Fatal("字段校验错误信息错误", errs[0], errs[1])
+	}
+	errs.Localize("en")
+	if errs[0].Message != "Name must be at most 5 bytes" || errs[1].Message != "Code只能包含小写字母:AB" {
+		t.Fatal("字段校验错误信息本地化错误", errs[0], errs[1])
+	}
+}
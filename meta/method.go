@@ -12,15 +12,25 @@ type MethodMetadata struct {
 
 // Generate 根据vc提供的值生成相应值
 //  return:函数的返回值数组([]interface{})
+//  参数未通过校验时返回所有参数的ValidationErrors
 func (this *MethodMetadata) Generate(vc ValueContainer) (interface{}, error) {
 	var params = make([]reflect.Value, 0, len(this.Params))
+	var errs ValidationErrors
 	for _, sMd := range this.Params {
 		var p, err = sMd.Generate(vc)
 		if err != nil {
-			return nil, err
+			var ok bool
+			errs, ok = collectErrors(errs, err)
+			if !ok {
+				return nil, err
+			}
+			continue
 		}
 		params = append(params, reflect.ValueOf(p))
 	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
 	var resultValue = this.Method.Call(params)
 	var result = make([]interface{}, 0, len(resultValue))
 	for _, v := range resultValue {
@@ -0,0 +1,132 @@
+package meta
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// 嵌套值容器,能够为结构体,切片和map类型的字段提供子值容器
+type NestedValueContainer interface {
+	ValueContainer
+	// Nested 返回名称为name的字段的子值容器,子值容器使用子字段名称,切片索引或map的key查找值
+	Nested(name string) NestedValueContainer
+	// Keys 返回名称为name的字段包含的子字段名称,切片索引或map的key,不存在时返回空数组
+	Keys(name string) []string
+}
+
+// 嵌套值提供器
+type nestedValueProvider struct {
+	strs  []string    //用于验证的字符串信息
+	value interface{} //生成的值
+}
+
+// String 返回用于验证的字符串值
+func (this *nestedValueProvider) String() []string {
+	return this.strs
+}
+
+// Value 返回生成的值
+func (this *nestedValueProvider) Value() interface{} {
+	return this.value
+}
+
+// GenerateNested 使用嵌套值容器生成名称为name的字段的值
+//  t:字段类型,可以是结构体,结构体指针,切片或key为字符串的map,切片和map的元素可以是以上类型或者vc能够直接提供的类型
+//  结构体的字段使用字段的vld标签进行校验,切片和map中能够直接提供的元素的字符串值由ValueProvider.String()返回
+//  切片只使用非负整数索引,并按索引顺序排列,不连续的索引会被压缩
+//  子字段未通过校验时返回ValidationErrors,字段名称包含name前缀
+func GenerateNested(vc NestedValueContainer, name string, t reflect.Type) (ValueProvider, bool, error) {
+	var keys = vc.Keys(name)
+	if len(keys) <= 0 {
+		return nil, false, nil
+	}
+	var sub = vc.Nested(name)
+	switch {
+	case t.Kind() == reflect.Struct || IsStructPtrType(t):
+		var g, err = AnalyzeStruct(t)
+		if err != nil {
+			return nil, false, err
+		}
+		var v, e = g.Generate(sub)
+		if e != nil {
+			return nil, false, prefixErrors(e, name)
+		}
+		return &nestedValueProvider{[]string{}, v}, true, nil
+	case t.Kind() == reflect.Slice:
+		var indexes = make([]int, 0, len(keys))
+		for _, k := range keys {
+			var i, err = strconv.Atoi(k)
+			if err == nil && i >= 0 {
+				indexes = append(indexes, i)
+			}
+		}
+		if len(indexes) <= 0 {
+			return nil, false, nil
+		}
+		sort.Ints(indexes)
+		var result = reflect.MakeSlice(t, 0, len(indexes))
+		var strs = make([]string, 0, len(indexes))
+		var errs ValidationErrors
+		for _, i := range indexes {
+			var vp, ok, err = generateElement(sub, strconv.Itoa(i), t.Elem())
+			if err != nil {
+				var isVld bool
+				errs, isVld = collectErrors(errs, err)
+				if !isVld {
+					return nil, false, err
+				}
+				continue
+			}
+			if ok {
+				result = reflect.Append(result, reflect.ValueOf(vp.Value()))
+				strs = append(strs, vp.String()...)
+			}
+		}
+		if len(errs) > 0 {
+			return nil, false, prefixErrors(errs, name)
+		}
+		return &nestedValueProvider{strs, result.Interface()}, true, nil
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		var result = reflect.MakeMapWithSize(t, len(keys))
+		var strs = make([]string, 0, len(keys))
+		var errs ValidationErrors
+		for _, k := range keys {
+			var vp, ok, err = generateElement(sub, k, t.Elem())
+			if err != nil {
+				var isVld bool
+				errs, isVld = collectErrors(errs, err)
+				if !isVld {
+					return nil, false, err
+				}
+				continue
+			}
+			if ok {
+				result.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), reflect.ValueOf(vp.Value()))
+				strs = append(strs, vp.String()...)
+			}
+		}
+		if len(errs) > 0 {
+			return nil, false, prefixErrors(errs, name)
+		}
+		return &nestedValueProvider{strs, result.Interface()}, true, nil
+	}
+	return nil, false, nil
+}
+
+// generateElement 生成切片或map的元素,元素值为nil或者类型不匹配时返回false
+func generateElement(vc NestedValueContainer, key string, t reflect.Type) (ValueProvider, bool, error) {
+	var vp, ok = vc.Contains(key, t)
+	var err error
+	if !ok {
+		vp, ok, err = GenerateNested(vc, key, t)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+	}
+	var value = vp.Value()
+	if value == nil || !reflect.TypeOf(value).AssignableTo(t) {
+		return nil, false, nil
+	}
+	return vp, true, nil
+}
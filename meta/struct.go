@@ -1,27 +1,53 @@
 package meta
 
-import "reflect"
+import (
+	"reflect"
+	"sync"
+)
 
 // 结构体元数据
 type StructMetadata struct {
 	Name   string           //结构体全名(包含包名)
 	Struct reflect.Type     //结构体类型
 	Fields []*FieldMetadata //结构体字段元数据
+	Errors *FieldMetadata   //ValidationErrors类型的字段元数据,不存在时为nil
 }
 
 // New 根据vp提供的值生成相应结构体值的指针
+//  所有字段都会进行校验,存在未通过校验的字段时返回ValidationErrors,结构体包含ValidationErrors类型的字段时校验错误注入到该字段中
 func (this *StructMetadata) New(vc ValueContainer) (interface{}, error) {
 	var vp, ok = vc.Contains(this.Name, this.Struct)
 	if ok {
 		return vp.Value(), nil
 	}
+	var svc, isStruct = vc.(StructValueContainer)
+	if isStruct {
+		var c, err = svc.StructContainer(this.Struct)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			vc = c
+		}
+	}
 	var result = reflect.New(this.Struct)
+	var errs ValidationErrors
 	for _, fMd := range this.Fields {
 		var err = fMd.Set(result, vc)
 		if err != nil {
-			return nil, err
+			var ok bool
+			errs, ok = collectErrors(errs, err)
+			if !ok {
+				return nil, err
+			}
 		}
 	}
+	if len(errs) > 0 {
+		if this.Errors == nil {
+			return nil, errs
+		}
+		result.Elem().FieldByIndex(this.Errors.Field.Index).Set(reflect.ValueOf(errs))
+	}
 	return result.Interface(), nil
 }
 
@@ -54,8 +80,11 @@ func (this *StructPtrMetadata) Generate(vc ValueContainer) (interface{}, error)
 	return this.Ptr.New(vc)
 }
 
-// 全局结构体元数据信息
-var globalStructMetadata = make(map[string]*StructMetadata)
+// 全局结构体元数据信息,嵌套的结构体在生成值时分析,需要加锁
+var (
+	globalStructMetadata   = make(map[string]*StructMetadata)
+	globalStructMetadataMu sync.RWMutex
+)
 
 // AnalyzeField 分析结构体
 //  s:结构体类型,必须是结构体或者结构体指针
@@ -67,7 +96,9 @@ func AnalyzeStruct(s reflect.Type) (Generator, error) {
 	if IsStructPtrType(s) {
 		st = s.Elem()
 	}
+	globalStructMetadataMu.RLock()
 	var sMd, ok = globalStructMetadata[st.String()]
+	globalStructMetadataMu.RUnlock()
 	if !ok {
 		sMd = new(StructMetadata)
 		sMd.Name = st.String()
@@ -78,13 +109,20 @@ func AnalyzeStruct(s reflect.Type) (Generator, error) {
 			if e != nil {
 				return e
 			}
+			if field.Type == validationErrorsType {
+				//校验错误字段不从值容器中注入
+				fMd.Kind = FieldKindIgnore
+				sMd.Errors = fMd
+			}
 			sMd.Fields = append(sMd.Fields, fMd)
 			return nil
 		})
 		if err != nil {
 			return nil, err
 		}
+		globalStructMetadataMu.Lock()
 		globalStructMetadata[st.String()] = sMd
+		globalStructMetadataMu.Unlock()
 	}
 
 	// 当参数是结构体指针时,构造结构体指针元数据
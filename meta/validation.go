@@ -0,0 +1,124 @@
+package meta
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/kdada/tinygo/validator"
+)
+
+// 字段校验错误
+type FieldError struct {
+	Field   string `json:"field" xml:"field"`     //字段名称,嵌套字段使用.连接,例如Items.0.Qty
+	Rule    string `json:"rule" xml:"rule"`       //未通过的校验规则,字段值不存在时为!
+	Index   int    `json:"index" xml:"index"`     //未通过校验的值的索引,字段值不存在时为-1
+	Value   string `json:"value" xml:"value"`     //未通过校验的值
+	Message string `json:"message" xml:"message"` //错误信息
+
+	name     string                        //字段名称,用于替换错误信息中的{field}
+	node     *validator.ExecutableFuncNode //导致校验失败的函数节点,值不存在时为nil
+	template string                        //msg标签
+	fallback string                        //不存在错误信息模板时使用的错误信息
+}
+
+// Error 返回错误信息
+func (this *FieldError) Error() string {
+	return this.Message
+}
+
+// Localize 使用指定语言的错误信息模板重新生成错误信息
+//  优先使用字段的msg标签,其次使用导致校验失败的验证函数的错误信息模板,都不存在时使用默认的错误信息
+func (this *FieldError) Localize(locale string) {
+	var template, ok = "", false
+	if this.template != "" {
+		template, ok = validator.Message(locale, this.template)
+		if !ok {
+			template, ok = this.template, true
+		}
+	} else if this.node != nil {
+		template, ok = validator.Message(locale, this.node.Name())
+	} else if this.Index < 0 {
+		template, ok = validator.Message(locale, "required")
+	}
+	if !ok {
+		this.Message = this.fallback
+		return
+	}
+	template = strings.Replace(template, "{field}", this.name, -1)
+	this.Message = validator.ExpandMessage(template, this.node, this.Value)
+}
+
+// 校验错误集合,包含结构体所有未通过校验的字段
+//  结构体包含ValidationErrors类型的字段时,校验错误注入到该字段中,否则作为错误返回
+type ValidationErrors []*FieldError
+
+// Error 返回所有字段的错误信息
+func (this ValidationErrors) Error() string {
+	var msgs = make([]string, len(this))
+	for i, e := range this {
+		msgs[i] = e.Message
+	}
+	return strings.Join(msgs, ";")
+}
+
+// Localize 使用指定语言的错误信息模板重新生成所有错误信息
+func (this ValidationErrors) Localize(locale string) ValidationErrors {
+	for _, e := range this {
+		e.Localize(locale)
+	}
+	return this
+}
+
+// ValidationErrors类型
+var validationErrorsType = reflect.TypeOf(ValidationErrors{})
+
+// collectErrors 将err中的校验错误添加到errs中,err不是校验错误时返回false
+func collectErrors(errs ValidationErrors, err error) (ValidationErrors, bool) {
+	switch e := err.(type) {
+	case *FieldError:
+		return append(errs, e), true
+	case ValidationErrors:
+		return append(errs, e...), true
+	}
+	return errs, false
+}
+
+// prefixErrors 为校验错误的字段名称添加前缀,err不是校验错误时原样返回
+func prefixErrors(err error, prefix string) error {
+	var errs, ok = collectErrors(nil, err)
+	if !ok {
+		return err
+	}
+	var result = make(ValidationErrors, len(errs))
+	for i, e := range errs {
+		var fe = *e
+		fe.Field = prefix + "." + fe.Field
+		result[i] = &fe
+	}
+	return result
+}
+
+// newFieldError 创建字段校验错误,错误信息使用validator.DefaultLocale生成
+//  index:未通过校验的值的索引,字段值不存在时为-1
+//  node:导致校验失败的函数节点,值不存在或者验证器不是validator.ExtendedValidator时为nil
+//  msg:不存在错误信息模板时使用的错误信息
+func newFieldError(fMd *FieldMetadata, index int, value string, node *validator.ExecutableFuncNode, msg Error) *FieldError {
+	var rule = fMd.Rule
+	if index < 0 {
+		rule = "!"
+	} else if node != nil {
+		rule = node.String()
+	}
+	var e = &FieldError{
+		Field:    fMd.Name,
+		Rule:     rule,
+		Index:    index,
+		Value:    value,
+		name:     fMd.Name,
+		node:     node,
+		template: fMd.Message,
+		fallback: msg.String(),
+	}
+	e.Localize(validator.DefaultLocale)
+	return e
+}
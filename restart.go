@@ -0,0 +1,93 @@
+package tinygo
+
+import "time"
+
+// App重启模式
+type RestartMode byte
+
+const (
+	RestartNever     RestartMode = iota //从不重启
+	RestartAlways                       //App运行结束后总是重启
+	RestartOnFailure                    //App运行出错或崩溃后重启
+)
+
+// App重启策略
+type RestartPolicy struct {
+	Mode        RestartMode   //重启模式
+	Backoff     time.Duration //首次重启前的等待时间,之后每次重启等待时间翻倍
+	MaxBackoff  time.Duration //重启前等待时间的上限
+	MaxRestarts int           //Window时间内允许的最大重启次数,超过后不再重启,0表示不限制
+	Window      time.Duration //统计重启次数的时间窗口
+}
+
+// NewRestartPolicy 创建重启策略,默认首次等待1秒,最多等待30秒,1分钟内最多重启5次
+func NewRestartPolicy(mode RestartMode) *RestartPolicy {
+	return &RestartPolicy{
+		Mode:        mode,
+		Backoff:     time.Second,
+		MaxBackoff:  30 * time.Second,
+		MaxRestarts: 5,
+		Window:      time.Minute,
+	}
+}
+
+// 可设置重启策略的App
+type RestartableApp interface {
+	App
+	// RestartPolicy 返回App的重启策略
+	RestartPolicy() *RestartPolicy
+}
+
+// SetRestartPolicy 设置名称为name的App的重启策略,优先级高于RestartableApp返回的重启策略
+func (this *Manager) SetRestartPolicy(name string, policy *RestartPolicy) {
+	this.lazyInit()
+	this.policies[name] = policy
+}
+
+// RestartPolicy 返回app的重启策略,没有设置重启策略时返回nil
+func (this *Manager) RestartPolicy(app App) *RestartPolicy {
+	var policy, ok = this.policies[app.Name()]
+	if ok {
+		return policy
+	}
+	var r, ok2 = app.(RestartableApp)
+	if ok2 {
+		return r.RestartPolicy()
+	}
+	return nil
+}
+
+// 重启记录
+type restarter struct {
+	policy   *RestartPolicy //重启策略
+	restarts []time.Time    //Window时间内的重启时间
+}
+
+// next 根据本次运行结果计算下一次重启前的等待时间
+//  failed:本次运行是否出错或崩溃
+//  return:(等待时间,Window时间内的重启次数,是否需要重启)
+func (this *restarter) next(failed bool) (time.Duration, int, bool) {
+	var p = this.policy
+	if p == nil || p.Mode == RestartNever || (p.Mode == RestartOnFailure && !failed) {
+		return 0, 0, false
+	}
+	//清除Window之外的重启记录
+	var now = time.Now()
+	var i = 0
+	for i < len(this.restarts) && now.Sub(this.restarts[i]) > p.Window {
+		i++
+	}
+	this.restarts = this.restarts[i:]
+	if p.MaxRestarts > 0 && len(this.restarts) >= p.MaxRestarts {
+		return 0, len(this.restarts), false
+	}
+	var delay = p.Backoff
+	for j := 0; j < len(this.restarts) && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); j++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	this.restarts = append(this.restarts, now)
+	return delay, len(this.restarts), true
+}
@@ -1,8 +1,10 @@
 package router
 
 import (
+	"net/url"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -12,16 +14,25 @@ type BaseRouter struct {
 	children         map[string]Router //所有子路由
 	normalChildren   map[string]Router //通常子路由
 	abnormalChildren map[string]Router //非通常子路由
+	abnormalOrder    []Router          //非通常子路由的匹配顺序
 	match            string            //用于匹配的字符串,可能包含正则信息
 	reg              bool              //是否是正则路由
 	regexp           *regexp.Regexp    //正则表达式
 	keys             []string          //可提取keys
+	segment          *RegSegment       //正则段信息
 }
 
 // NewBaseRouter 创建基本路由,非正则路由不区分大小写,正则路由是否区分大小写由正则表达式确定
 //  name:路由名称,如果match为nil,则使用name进行路由匹配
 //  match:用于进行匹配的值,可以包含指定规则的正则字符串,格式可以为p{id=\d+}.html 解析为 ^p(\d+).html$
+//   也可以使用类型参数,例如p{id:int}.html,类型参数在匹配时解析,可以通过RouterContext.Param获取解析后的值
+//   path类型的参数可以匹配多个路由段,例如{rest:path},剩余的路由段优先由子路由匹配
 func NewBaseRouter(name string, match interface{}) (Router, error) {
+	return newBaseRouter(name, match)
+}
+
+// newBaseRouter 创建基本路由,继承基本路由的路由在创建后需要重新设置self
+func newBaseRouter(name string, match interface{}) (*BaseRouter, error) {
 	var r = new(BaseRouter)
 	r.name = name
 	if match != nil {
@@ -35,6 +46,7 @@ func NewBaseRouter(name string, match interface{}) (Router, error) {
 			r.reg = true
 			r.regexp = seg.Regexp
 			r.keys = seg.Keys
+			r.segment = seg
 		} else {
 			r.reg = false
 		}
@@ -46,6 +58,7 @@ func NewBaseRouter(name string, match interface{}) (Router, error) {
 	r.abnormalChildren = make(map[string]Router, 0)
 	r.preFilters = make([]PreFilter, 0)
 	r.postFilters = make([]PostFilter, 0)
+	r.aroundFilters = make([]AroundFilter, 0)
 	r.self = r
 	return r, nil
 }
@@ -58,8 +71,7 @@ func (this *BaseRouter) MatchString() string {
 // SetParent 设置当前路由父路由,当前路由必须是父路由的子路由
 func (this *BaseRouter) SetParent(router Router) error {
 	var r, ok = router.Child(this.name)
-	var x, ok2 = r.(*BaseRouter)
-	if ok && ok2 && x == this {
+	if ok && r == this.self {
 		if this.parent != nil && this.parent != router {
 			this.parent.RemoveChild(this.name)
 		}
@@ -69,6 +81,37 @@ func (this *BaseRouter) SetParent(router Router) error {
 	return ErrorInvalidParentRouter.Error()
 }
 
+// BuildSegment 生成能够匹配当前路由的路由段,正则路由使用values中的值填充
+func (this *BaseRouter) BuildSegment(values map[string]string) (string, []string, error) {
+	if !this.reg {
+		return url.PathEscape(this.match), nil, nil
+	}
+	var seg, err = this.segment.Build(values)
+	if err != nil {
+		return "", nil, err
+	}
+	if this.segment.Multi {
+		//多个路由段分别转义
+		var parts = strings.Split(strings.Trim(seg, "/"), "/")
+		for i, p := range parts {
+			if p == "" || strings.Contains(p, `\`) {
+				return "", nil, ErrorInvalidSegment.Format(this.name, seg).Error()
+			}
+			parts[i] = url.PathEscape(p)
+		}
+		return strings.Join(parts, "/"), this.keys, nil
+	}
+	if strings.ContainsAny(seg, `/\`) {
+		return "", nil, ErrorInvalidSegment.Format(this.name, seg).Error()
+	}
+	return url.PathEscape(seg), this.keys, nil
+}
+
+// Keys 返回当前路由能够提取的路由值名称,通常路由没有路由值
+func (this *BaseRouter) Keys() []string {
+	return append([]string{}, this.keys...)
+}
+
 // Normal 返回当前路由是否为通常路由,通常路由可以使用MatchString()返回的字符串进行相等匹配
 func (this *BaseRouter) Normal() bool {
 	return !this.reg
@@ -86,14 +129,43 @@ func (this *BaseRouter) AddChild(router Router) {
 		//合并路由
 		child.AddChildren(router.Children())
 	} else {
+		var inv = findInvalidator(this.self)
+		if inv != nil {
+			//添加之前检查冲突,冲突时路由保持不变
+			var err = inv.check(append(this.Children(), router))
+			if err != nil {
+				panic(err)
+			}
+		}
 		//添加路由
 		if router.Normal() {
 			this.normalChildren[this.unify(router.MatchString())] = router
 		} else {
 			this.abnormalChildren[router.Name()] = router
+			this.sortAbnormal()
 		}
 		this.children[router.Name()] = router
-		router.SetParent(this)
+		router.SetParent(this.self)
+	}
+	this.changed()
+}
+
+// findInvalidator 从r开始沿父路由向上查找最近的invalidator,找不到时返回nil
+func findInvalidator(r Router) invalidator {
+	for ; r != nil; r = r.Parent() {
+		var inv, ok = r.(invalidator)
+		if ok {
+			return inv
+		}
+	}
+	return nil
+}
+
+// changed 子路由发生变化后通知最近的invalidator(例如树路由)检查冲突并重新编译
+func (this *BaseRouter) changed() {
+	var inv = findInvalidator(this.parent)
+	if inv != nil {
+		inv.invalidate(this.self)
 	}
 }
 
@@ -104,19 +176,57 @@ func (this *BaseRouter) AddChildren(routers []Router) {
 	}
 }
 
+// sortAbnormal 确定非通常子路由的匹配顺序,单路由段的正则路由最先匹配,然后是多路由段的正则路由,最后是无限路由,相同类型的路由按名称排序
+func (this *BaseRouter) sortAbnormal() {
+	var routers = make([]Router, 0, len(this.abnormalChildren))
+	for _, v := range this.abnormalChildren {
+		routers = append(routers, v)
+	}
+	sort.Slice(routers, func(i, j int) bool {
+		var ri = matchRank(routers[i])
+		var rj = matchRank(routers[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return routers[i].Name() < routers[j].Name()
+	})
+	this.abnormalOrder = routers
+}
+
+// matchRank 返回路由的匹配优先级,值越小越先匹配
+func matchRank(r Router) int {
+	if isUnlimited(r) {
+		return 2
+	}
+	var b, ok = r.(*BaseRouter)
+	if ok && b.reg && b.segment.Multi {
+		return 1
+	}
+	return 0
+}
+
+// isUnlimited 判断路由是否为无限路由
+func isUnlimited(r Router) bool {
+	var _, ok = r.(*UnlimitedRouter)
+	return ok
+}
+
 // Child 返回指定名称的子路由
 func (this *BaseRouter) Child(name string) (Router, bool) {
 	var r, ok = this.children[name]
 	return r, ok
 }
 
-// Children 返回全部子路由
+// Children 返回全部子路由,按名称排序
 func (this *BaseRouter) Children() []Router {
-	var routers = make([]Router, len(this.children))
+	var routers = make([]Router, 0, len(this.children))
 	for _, v := range this.children {
 		routers = append(routers, v)
 	}
-	return []Router{}
+	sort.Slice(routers, func(i, j int) bool {
+		return routers[i].Name() < routers[j].Name()
+	})
+	return routers
 }
 
 // RemoveChild 移除指定名称的路由,并返回该路由
@@ -128,7 +238,9 @@ func (this *BaseRouter) RemoveChild(name string) (Router, bool) {
 			delete(this.normalChildren, this.unify(r.MatchString()))
 		} else {
 			delete(this.abnormalChildren, name)
+			this.sortAbnormal()
 		}
+		this.changed()
 		return r, ok
 	}
 	return nil, false
@@ -138,11 +250,16 @@ func (this *BaseRouter) RemoveChild(name string) (Router, bool) {
 func (this *BaseRouter) Find(context RouterContext) (Router, bool) {
 	//获取当前路由段
 	var segs = context.Segments()
-	var data [][]string
+	if this.reg && this.segment.Multi {
+		return this.findMulti(context, segs)
+	}
+	var raws []string
+	var values []interface{}
 	if !this.Normal() {
 		//正则路由检查
-		data = this.regexp.FindAllStringSubmatch(segs[0], 1)
-		if len(data) <= 0 {
+		var ok bool
+		raws, values, ok = this.segment.Match(segs[0])
+		if !ok {
 			//无法匹配当前正则路由,直接返回
 			return nil, false
 		}
@@ -152,11 +269,36 @@ func (this *BaseRouter) Find(context RouterContext) (Router, bool) {
 			return nil, false
 		}
 	}
-	//增加1个匹配级别
-	context.Match(1)
+	return this.findChildren(context, 1, raws, values)
+}
+
+// findMulti 匹配可以包含多个路由段的正则路由,优先匹配更多的路由段,剩余的路由段必须能够被子路由匹配
+func (this *BaseRouter) findMulti(context RouterContext, segs []string) (Router, bool) {
+	//最后尝试匹配全部路由段
+	for count := len(segs) - 1; count >= 0; count-- {
+		var n = count
+		if n == 0 {
+			n = len(segs)
+		}
+		var raws, values, ok = this.segment.Match(strings.Join(segs[:n], "/"))
+		if !ok {
+			continue
+		}
+		var router, found = this.findChildren(context, n, raws, values)
+		if found {
+			return router, true
+		}
+	}
+	return nil, false
+}
+
+// findChildren 当前路由匹配count个路由段后,使用子路由匹配剩余的路由段,匹配成功后设置路由值
+func (this *BaseRouter) findChildren(context RouterContext, count int, raws []string, values []interface{}) (Router, bool) {
+	//增加匹配级别
+	context.Match(count)
 	//获取子级路由段
-	segs = context.Segments()
-	var router Router = this
+	var segs = context.Segments()
+	var router = this.self
 	if len(segs) > 0 {
 		//路由段未匹配完成则传递给子路由
 		var match = this.unify(segs[0])
@@ -167,7 +309,7 @@ func (this *BaseRouter) Find(context RouterContext) (Router, bool) {
 		}
 		if !ok {
 			//检查非常规子路由
-			for _, v := range this.abnormalChildren {
+			for _, v := range this.abnormalOrder {
 				router, ok = v.Find(context)
 				if ok {
 					break
@@ -175,17 +317,12 @@ func (this *BaseRouter) Find(context RouterContext) (Router, bool) {
 			}
 		}
 		if !ok {
-			//未能匹配成功,减少1个匹配级别
-			context.Unmatch(1)
+			//未能匹配成功,减少匹配级别
+			context.Unmatch(count)
 			return nil, false
 		}
 	}
 	//匹配成功,保持路由级别并设置路由值
-	if len(data) >= 1 {
-		var values = data[0][1:]
-		for i, v := range values {
-			context.SetValue(this.keys[i], v)
-		}
-	}
+	setValues(context, this.keys, raws, values)
 	return router, true
 }
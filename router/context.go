@@ -4,8 +4,10 @@ import "regexp"
 
 // 基础路由上下文
 type BaseContext struct {
-	Segs  []string //路由段信息
-	Level int      //当前路由级别
+	Segs   []string               //路由段信息
+	Level  int                    //当前路由级别
+	values map[string]string      //路由值
+	params map[string]interface{} //解析后的路由参数
 }
 
 // 分隔符正则表达式
@@ -16,8 +18,8 @@ func NewBaseContext(path string) *BaseContext {
 	var segs = spReg.Split(path+"/", -1)
 	segs = segs[:len(segs)-1]
 	return &BaseContext{
-		segs,
-		0,
+		Segs:  segs,
+		Level: 0,
 	}
 }
 
@@ -56,10 +58,28 @@ func (this *BaseContext) Pure() bool {
 
 // Value 返回路由值
 func (this *BaseContext) Value(name string) (string, bool) {
-	return "", false
+	var v, ok = this.values[name]
+	return v, ok
 }
 
 // SetValue 设置路由值
 func (this *BaseContext) SetValue(name string, value string) {
+	if this.values == nil {
+		this.values = make(map[string]string)
+	}
+	this.values[name] = value
+}
 
+// Param 返回解析后的路由参数
+func (this *BaseContext) Param(name string) (interface{}, bool) {
+	var v, ok = this.params[name]
+	return v, ok
+}
+
+// SetParam 设置解析后的路由参数
+func (this *BaseContext) SetParam(name string, value interface{}) {
+	if this.params == nil {
+		this.params = make(map[string]interface{})
+	}
+	this.params[name] = value
 }
@@ -31,10 +31,19 @@ const (
 	ErrorRegexpNoneError      Error = "ErrorRegexpNoneError(R10031),字符串(%s)不包含正则表达式"
 	ErrorRegexpFormatError    Error = "ErrorRegexpFormatError(R10032),字符串(%s)格式错误"
 	ErrorRegexpNotMatchError  Error = "ErrorRegexpNotMatchError(R10033),正则表达式匹配失败"
+	ErrorRouterValueNotFound  Error = "ErrorRouterValueNotFound(R10034),缺少路由值(%s)"
+	ErrorInvalidRouterValue   Error = "ErrorInvalidRouterValue(R10035),路由值%s(%s)不匹配正则表达式(%s)"
+	ErrorUnknownParamType     Error = "ErrorUnknownParamType(R10036),未知的路由参数类型(%s)"
+	ErrorInvalidParamType     Error = "ErrorInvalidParamType(R10037),无效的路由参数类型(%s)"
+	ErrorInvalidParamValue    Error = "ErrorInvalidParamValue(R10038),路由参数%s(%s)无法解析为%s类型"
 	ErrorExecutorDoNothing    Error = "ErrorExecutorDoNothing(R10050),空执行器错误,该执行器没有执行任何内容"
 	ErrorPreFilterNotPass     Error = "ErrorPreFilterNotPass(R10051),前置过滤未通过"
 	ErrorPostFilterNotPass    Error = "ErrorPostFilterNotPass(R10052),后置过滤未通过"
 	ErrorInvalidKind          Error = "ErrorInvalidKind(R10060),无效的路由类型(%s)"
 	ErrorInvalidRouterCreator Error = "ErrorInvalidRouterCreator(R10070),无效的路由创建器"
 	ErrorInvalidMatchParam    Error = "ErrorInvalidMatchParam(R10080),无效的match参数(%s),期望参数类型为%s"
+	ErrorAliasNotFound        Error = "ErrorAliasNotFound(R10090),路由别名(%s)不存在"
+	ErrorDuplicateAlias       Error = "ErrorDuplicateAlias(R10091),存在多个别名为(%s)的路由"
+	ErrorInvalidSegment       Error = "ErrorInvalidSegment(R10092),路由(%s)生成的路由段(%s)无效"
+	ErrorRouteConflict        Error = "ErrorRouteConflict(R10100),路由(%s)与路由(%s)冲突"
 )
@@ -34,7 +34,26 @@ func (this *BaseRouterExecutor) Execute() (interface{}, error) {
 }
 
 // FilterExecute 执行f方法并使用过滤器,未通过过滤器时返回相应错误
+//  环绕过滤器由根路由到End依次包装前置过滤器,f方法和后置过滤器的执行过程
 func (this *BaseRouterExecutor) FilterExecute(f func() (interface{}, error)) (interface{}, error) {
+	var filters = make([]AroundFilter, 0)
+	for r := this.End; r != nil; r = r.Parent() {
+		filters = append(r.AroundFilters(), filters...)
+	}
+	var next AroundNext = func() (interface{}, error) {
+		return this.filterExecute(f)
+	}
+	for i := len(filters) - 1; i >= 0; i-- {
+		var filter, inner = filters[i], next
+		next = func() (interface{}, error) {
+			return filter.Around(this.Context, inner)
+		}
+	}
+	return next()
+}
+
+// filterExecute 执行f方法并使用前置过滤器和后置过滤器
+func (this *BaseRouterExecutor) filterExecute(f func() (interface{}, error)) (interface{}, error) {
 	// 执行前置过滤器
 	if this.ExecutePreFilters() {
 		//执行处理方法
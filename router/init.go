@@ -3,4 +3,5 @@ package router
 func init() {
 	Register("unlimited", NewUnlimitedRouter)
 	Register("base", NewBaseRouter)
+	Register("tree", NewTreeRouter)
 }
@@ -0,0 +1,109 @@
+package router
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 路由参数解析方法,将匹配的字符串转换为相应类型的值
+type ParamParser func(value string) (interface{}, error)
+
+// 路由参数类型,在正则路由中使用{name:type}格式声明
+type ParamType struct {
+	Exp   string      //参数的正则表达式,不能包含捕获分组
+	Multi bool        //参数是否可以匹配多个路由段(包含/)
+	Parse ParamParser //参数解析方法,为nil时参数值为字符串
+	Check ParamCheck  //快速检查值是否完全匹配Exp,可以为nil,不为nil时只包含一个类型参数的正则段不使用正则表达式进行匹配
+}
+
+// 路由参数检查方法,返回值是否完全匹配参数类型的正则表达式
+type ParamCheck func(value string) bool
+
+var (
+	paramMu    sync.RWMutex             //参数类型互斥锁
+	paramTypes = map[string]*ParamType{ //参数类型映射
+		//64位整数,解析为int64
+		"int": {`-?[0-9]+`, false, parseIntParam, checkInt},
+		//英文字母
+		"alpha": {`[a-zA-Z]+`, false, nil, checkAlpha},
+		//uuid
+		"uuid": {`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`, false, nil, checkUUID},
+		//一个或多个路由段,值中可以包含/
+		"path": {`.+`, true, nil, nil},
+	}
+)
+
+// parseIntParam 解析int参数
+func parseIntParam(value string) (interface{}, error) {
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// checkInt 检查int参数
+func checkInt(value string) bool {
+	if strings.HasPrefix(value, "-") {
+		value = value[1:]
+	}
+	if value == "" {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// checkAlpha 检查alpha参数
+func checkAlpha(value string) bool {
+	if value == "" {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		var c = value[i] | 0x20
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// checkUUID 检查uuid参数
+func checkUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		var c = value[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// RegisterParamType 注册路由参数类型,相同名称的类型会被替换
+func RegisterParamType(name string, t *ParamType) {
+	if t == nil || t.Exp == "" {
+		panic(ErrorInvalidParamType.Format(name))
+	}
+	paramMu.Lock()
+	defer paramMu.Unlock()
+	paramTypes[name] = t
+}
+
+// FindParamType 返回指定名称的路由参数类型
+func FindParamType(name string) (*ParamType, bool) {
+	paramMu.RLock()
+	defer paramMu.RUnlock()
+	var t, ok = paramTypes[name]
+	return t, ok
+}
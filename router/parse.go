@@ -5,11 +5,18 @@ import (
 	"strings"
 )
 
-//正则段
+// 正则段
 type RegSegment struct {
-	Exp    string         //正则表达式
-	Regexp *regexp.Regexp //编译后的正则表达式
-	Keys   []string       //可提取keys
+	Exp    string           //正则表达式
+	Regexp *regexp.Regexp   //编译后的正则表达式
+	Keys   []string         //可提取keys
+	Types  []string         //每个key的参数类型,使用正则表达式或未指定类型的key为空
+	Multi  bool             //是否包含可以匹配多个路由段的参数
+	texts  []string         //普通字符串,数量比Keys多1,依次位于每个key之前和最后一个key之后
+	exps   []string         //每个key对应的正则表达式
+	regs   []*regexp.Regexp //每个key对应的完整匹配的正则表达式,生成url时用于检查路由值,无法单独编译时为nil
+	params []*ParamType     //每个key对应的参数类型,没有类型时为nil
+	check  ParamCheck       //只包含一个类型参数并且普通字符串不包含正则表达式时使用的快速检查方法
 }
 
 // Parse 解析字符串并生成key-value形式的值
@@ -28,20 +35,114 @@ func (this *RegSegment) Parse(str string) (map[string]string, error) {
 	return nil, ErrorRegexpNotMatchError.Error()
 }
 
+// Match 匹配字符串并按Keys的顺序返回原始值和解析后的值,指定了类型的值使用类型的解析方法解析
+func (this *RegSegment) Match(str string) ([]string, []interface{}, bool) {
+	var raws []string
+	if this.check != nil {
+		//只包含一个类型参数并且普通字符串不包含正则表达式
+		var prefix, suffix = this.texts[0], this.texts[1]
+		if len(str) < len(prefix)+len(suffix) || !strings.HasPrefix(str, prefix) || !strings.HasSuffix(str, suffix) {
+			return nil, nil, false
+		}
+		var value = str[len(prefix) : len(str)-len(suffix)]
+		if !this.check(value) {
+			return nil, nil, false
+		}
+		raws = []string{value}
+	} else {
+		var data = this.Regexp.FindStringSubmatch(str)
+		if len(data) != len(this.Keys)+1 {
+			return nil, nil, false
+		}
+		raws = data[1:]
+	}
+	var values = make([]interface{}, len(raws))
+	for i, v := range raws {
+		var value, err = parseParam(this.params[i], v)
+		if err != nil {
+			return nil, nil, false
+		}
+		values[i] = value
+	}
+	return raws, values, true
+}
+
+// 正则表达式元字符
+const regexpMetaChars = `\.+*?()|[]{}^$`
+
+// isLiteral 判断字符串是否不包含正则表达式元字符
+func isLiteral(text string) bool {
+	return !strings.ContainsAny(text, regexpMetaChars)
+}
+
+// literalAffix 返回正则段一定包含的普通字符串前缀和后缀,用于在匹配正则表达式之前快速排除
+//  普通字符串中包含正则表达式时只使用第一个元字符之前和最后一个元字符之后的部分,包含|或者(?时不使用前缀和后缀
+func (this *RegSegment) literalAffix() (string, string) {
+	var first, last = this.texts[0], this.texts[len(this.texts)-1]
+	for _, t := range this.texts {
+		if strings.Contains(t, "|") || strings.Contains(t, "(?") {
+			return "", ""
+		}
+	}
+	var prefix = first
+	var i = strings.IndexAny(first, regexpMetaChars)
+	if i >= 0 {
+		prefix = first[:i]
+		if strings.ContainsRune("*?{", rune(first[i])) && len(prefix) > 0 {
+			//量词作用于前一个字符
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	var suffix = last
+	var j = strings.LastIndexAny(last, regexpMetaChars)
+	if j >= 0 {
+		suffix = last[j+1:]
+		if last[j] == '\\' {
+			//转义字符和之后的字符共同组成正则表达式
+			suffix = last[min(j+2, len(last)):]
+		}
+	}
+	return prefix, suffix
+}
+
+// wildcards 返回未指定正则表达式和类型的key的数量,即{key}形式的key的数量
+func (this *RegSegment) wildcards() int {
+	var count = 0
+	for i, exp := range this.exps {
+		if exp == ".*" && this.params[i] == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// parseParam 使用参数类型解析值,没有类型或类型没有解析方法时返回原始字符串
+func parseParam(t *ParamType, value string) (interface{}, error) {
+	if t == nil || t.Parse == nil {
+		return value, nil
+	}
+	return t.Parse(value)
+}
+
 // ParseReg 解析正则路由段字符串
+//  格式:{key=正则表达式},{key:类型}或{key},{key}等同于{key=.*}
+//  类型:int,alpha,uuid,path以及使用RegisterParamType注册的类型,path可以匹配多个路由段
 //  return:如果可以解析出正则内容,则返回RegSegment,否则返回nil
 func ParseReg(exp string) (*RegSegment, error) {
 	var rs = new(RegSegment)
 	rs.Keys = make([]string, 0)
+	rs.Types = make([]string, 0)
 	var bytes = []byte(exp)
 
 	var lastSegStart = 0
+	var text = ""
 	for i, b := range bytes {
 		switch b {
 		case 0x7B: //"{"
 			{
 				//截取普通字符串
 				rs.Exp += string(bytes[lastSegStart:i])
+				text += string(bytes[lastSegStart:i])
 				lastSegStart = i + 1
 			}
 		case 0x7D: //"}"
@@ -49,8 +150,21 @@ func ParseReg(exp string) (*RegSegment, error) {
 				//截取正则字符串
 				var reg = string(bytes[lastSegStart:i])
 				var pos = strings.Index(reg, "=")
-				var key, value = "", ""
-				if pos > 0 {
+				var colon = strings.Index(reg, ":")
+				var key, value, typeName = "", "", ""
+				var param *ParamType
+				if colon > 0 && (pos == -1 || colon < pos) {
+					//类型参数
+					key = reg[:colon]
+					typeName = reg[colon+1:]
+					var ok bool
+					param, ok = FindParamType(typeName)
+					if !ok {
+						return nil, ErrorUnknownParamType.Format(typeName).Error()
+					}
+					value = param.Exp
+					rs.Multi = rs.Multi || param.Multi
+				} else if pos > 0 {
 					key = reg[:pos]
 					value = reg[pos+1:]
 				} else if pos == -1 {
@@ -61,28 +175,67 @@ func ParseReg(exp string) (*RegSegment, error) {
 					return nil, ErrorRegexpFormatError.Format(reg).Error()
 				}
 				if key != "" {
+					//单独的正则表达式无法编译时只影响生成url
+					var keyReg, _ = regexp.Compile("^(?:" + value + ")$")
 					rs.Keys = append(rs.Keys, key)
+					rs.Types = append(rs.Types, typeName)
+					rs.params = append(rs.params, param)
 					rs.Exp += "(" + value + ")"
+					rs.texts = append(rs.texts, text)
+					rs.exps = append(rs.exps, value)
+					rs.regs = append(rs.regs, keyReg)
+					text = ""
 				}
 				lastSegStart = i + 1
 			}
 		}
 	}
 	if len(rs.Keys) > 0 {
-		if lastSegStart < len(bytes)-1 {
+		if lastSegStart < len(bytes) {
 			rs.Exp += string(bytes[lastSegStart:len(bytes)])
+			text += string(bytes[lastSegStart:len(bytes)])
 		}
+		rs.texts = append(rs.texts, text)
 		rs.Exp = "^" + rs.Exp + "$"
 		var err error
 		rs.Regexp, err = regexp.Compile(rs.Exp)
 		if err != nil {
 			return nil, ErrorRegexpParseError.Format(rs.Exp).Error()
 		}
+		if len(rs.params) == 1 && rs.params[0] != nil && isLiteral(rs.texts[0]) && isLiteral(rs.texts[1]) {
+			rs.check = rs.params[0].Check
+		}
 		return rs, nil
 	}
 	return nil, ErrorRegexpNoneError.Format(exp).Error()
 }
 
+// Build 使用values中的值生成能够匹配当前正则段的字符串,每个值都必须完全匹配相应key的正则表达式
+func (this *RegSegment) Build(values map[string]string) (string, error) {
+	var result = this.texts[0]
+	for i, key := range this.Keys {
+		var v, ok = values[key]
+		if !ok {
+			return "", ErrorRouterValueNotFound.Format(key).Error()
+		}
+		if this.regs[i] == nil {
+			return "", ErrorRegexpParseError.Format(this.exps[i]).Error()
+		}
+		if !this.regs[i].MatchString(v) {
+			return "", ErrorInvalidRouterValue.Format(key, v, this.exps[i]).Error()
+		}
+		if _, err := parseParam(this.params[i], v); err != nil {
+			return "", ErrorInvalidParamValue.Format(key, v, this.Types[i]).Error()
+		}
+		result += v + this.texts[i+1]
+	}
+	if !this.Regexp.MatchString(result) {
+		return "", ErrorInvalidRouterValue.Format(this.Keys, result, this.Exp).Error()
+	}
+	return result, nil
+}
+
+// ParseRegs 解析多个正则路由段字符串
 func ParseRegs(exps []string) ([]*RegSegment, error) {
 	var results = make([]*RegSegment, len(exps))
 	for i, s := range exps {
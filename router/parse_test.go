@@ -13,3 +13,52 @@ func TestParseReg(t *testing.T) {
 		t.Fatal(err2, "匹配数据错误")
 	}
 }
+
+func TestParseTypedReg(t *testing.T) {
+	var segment, err = ParseReg("p{id:int}_{name:alpha}")
+	if err != nil || segment.Exp != "^p(-?[0-9]+)_([a-zA-Z]+)$" || segment.Types[0] != "int" || segment.Types[1] != "alpha" {
+		t.Fatal(err, "解析错误")
+	}
+	var raws, values, ok = segment.Match("p12_abc")
+	if !ok || raws[0] != "12" || values[0] != int64(12) || values[1] != "abc" {
+		t.Fatal("匹配数据错误", raws, values)
+	}
+	_, _, ok = segment.Match("p99999999999999999999_abc")
+	if ok {
+		t.Fatal("溢出的整数不应当匹配")
+	}
+	_, _, ok = segment.Match("p12_a1")
+	if ok {
+		t.Fatal("非字母不应当匹配")
+	}
+	_, err = ParseReg("{id:unknown}")
+	if err == nil {
+		t.Fatal("未知类型应当返回错误")
+	}
+	segment, err = ParseReg("{rest:path}")
+	if err != nil || !segment.Multi {
+		t.Fatal(err, "path类型解析错误")
+	}
+}
+
+func TestLiteralAffix(t *testing.T) {
+	var cases = map[string][2]string{
+		"p{id:int}.html":    {"p", "html"},
+		"item{id:int}_x":    {"item", "_x"},
+		"ab*{id}cd":         {"a", "cd"},
+		`{id}\d`:            {"", ""},
+		"(?i)p{id}.html":    {"", ""},
+		"a|b{id}":           {"", ""},
+		"{a:int}-{b:alpha}": {"", ""},
+	}
+	for exp, affix := range cases {
+		var segment, err = ParseReg(exp)
+		if err != nil {
+			t.Fatal(exp, err)
+		}
+		var prefix, suffix = segment.literalAffix()
+		if prefix != affix[0] || suffix != affix[1] {
+			t.Errorf("%s: expected %q %q, got %q %q", exp, affix[0], affix[1], prefix, suffix)
+		}
+	}
+}
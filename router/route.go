@@ -0,0 +1,76 @@
+package router
+
+import "strings"
+
+// 路由信息,描述一个能够生成执行器的路由
+type Route struct {
+	Router        Router         //能够生成执行器的路由
+	Chain         []Router       //从根路由到Router的路由链
+	Keys          []string       //路由链上能够提取的路由值名称
+	Executor      RouterExcutor  //路由执行器
+	PreFilters    []PreFilter    //按执行顺序排列的前置过滤器(由根路由到Router)
+	PostFilters   []PostFilter   //按执行顺序排列的后置过滤器(由Router到根路由)
+	AroundFilters []AroundFilter //由外到内排列的环绕过滤器(由根路由到Router)
+}
+
+// Pattern 返回路由链对应的路径模式,正则路由使用原始匹配字符串,无限路由使用*
+func (this *Route) Pattern() string {
+	return Pattern(this.Chain)
+}
+
+// Routes 返回root及其子路由中所有能够生成执行器的路由,子路由按名称深度优先遍历
+func Routes(root Router) []*Route {
+	var routes = make([]*Route, 0)
+	var walk func(r Router, chain []Router)
+	walk = func(r Router, chain []Router) {
+		chain = append(chain[:len(chain):len(chain)], r)
+		var executor, ok = r.RouterExcutor()
+		if ok {
+			routes = append(routes, newRoute(chain, executor))
+		}
+		for _, child := range r.Children() {
+			walk(child, chain)
+		}
+	}
+	walk(root, nil)
+	return routes
+}
+
+// newRoute 根据路由链创建路由信息
+func newRoute(chain []Router, executor RouterExcutor) *Route {
+	var route = &Route{
+		Router:        chain[len(chain)-1],
+		Chain:         chain,
+		Keys:          make([]string, 0),
+		Executor:      executor,
+		PreFilters:    make([]PreFilter, 0),
+		PostFilters:   make([]PostFilter, 0),
+		AroundFilters: make([]AroundFilter, 0),
+	}
+	for _, r := range chain {
+		route.Keys = append(route.Keys, r.Keys()...)
+		route.PreFilters = append(route.PreFilters, r.PreFilters()...)
+		route.AroundFilters = append(route.AroundFilters, r.AroundFilters()...)
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		route.PostFilters = append(route.PostFilters, chain[i].PostFilters()...)
+	}
+	return route
+}
+
+// Pattern 返回路由链对应的路径模式,正则路由使用原始匹配字符串,无限路由使用*
+func Pattern(chain []Router) string {
+	var segs = make([]string, len(chain))
+	for i, r := range chain {
+		if isUnlimited(r) {
+			segs[i] = "*"
+		} else {
+			segs[i] = r.MatchString()
+		}
+	}
+	var path = strings.Join(segs, "/")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
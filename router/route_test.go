@@ -0,0 +1,150 @@
+package router
+
+import (
+	"fmt"
+	"testing"
+)
+
+type testFilter struct {
+	name string
+}
+
+func (this *testFilter) Filter(context RouterContext) bool {
+	return true
+}
+
+type testPostFilter struct {
+	name string
+}
+
+func (this *testPostFilter) Filter(context RouterContext, result interface{}) bool {
+	return true
+}
+
+type testAroundFilter struct {
+	name  string
+	trace *[]string
+	stop  bool
+}
+
+func (this *testAroundFilter) Around(context RouterContext, next AroundNext) (interface{}, error) {
+	*this.trace = append(*this.trace, this.name)
+	if this.stop {
+		return this.name, nil
+	}
+	var result, err = next()
+	*this.trace = append(*this.trace, "/"+this.name)
+	return result, err
+}
+
+func (this *testAroundFilter) Filter(context RouterContext) bool {
+	*this.trace = append(*this.trace, "pre")
+	return true
+}
+
+func TestAroundFilters(t *testing.T) {
+	var root, _ = NewRouter("base", "", nil)
+	var api, _ = NewRouter("base", "api", nil)
+	var get, _ = NewRouter("base", "Get", nil)
+	root.AddChild(api)
+	api.AddChild(get)
+	var trace = make([]string, 0)
+	var outer = &testAroundFilter{name: "root", trace: &trace}
+	var inner = &testAroundFilter{name: "api", trace: &trace}
+	root.AddAroundFilter(outer)
+	api.AddAroundFilter(inner).AddPreFilter(inner)
+	var executor = &BaseRouterExecutor{End: get, Context: NewBaseContext("/api/Get")}
+	var result, err = executor.FilterExecute(func() (interface{}, error) {
+		trace = append(trace, "exec")
+		return "exec", nil
+	})
+	if err != nil || result != "exec" || fmt.Sprint(trace) != "[root api pre exec /api /root]" {
+		t.Fatal("环绕过滤器执行顺序错误", result, err, trace)
+	}
+	trace = trace[:0]
+	inner.stop = true
+	result, err = executor.FilterExecute(func() (interface{}, error) {
+		trace = append(trace, "exec")
+		return "exec", nil
+	})
+	if err != nil || result != "api" || fmt.Sprint(trace) != "[root api /root]" {
+		t.Fatal("环绕过滤器结果错误", result, err, trace)
+	}
+	if r := newRoute([]Router{root, api, get}, executor); len(r.AroundFilters) != 2 || r.AroundFilters[0] != outer {
+		t.Fatal("路由信息中的环绕过滤器错误", r.AroundFilters)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	var root, _ = NewRouter("base", "", nil)
+	var posts, _ = NewRouter("base", "Posts", nil)
+	var detail, _ = NewRouter("base", "detail", `p{id=\d+}.html`)
+	var files, _ = NewRouter("unlimited", "files", nil)
+	var gen = func() RouterExcutor {
+		return &TestBaseExcutor{}
+	}
+	detail.SetRouterExcutorGenerator(gen)
+	files.SetRouterExcutorGenerator(gen)
+	root.AddChild(posts)
+	posts.AddChild(files)
+	posts.AddChild(detail)
+	var rootPre, postsPre = &testFilter{"root"}, &testFilter{"posts"}
+	var rootPost, detailPost = &testPostFilter{"root"}, &testPostFilter{"detail"}
+	root.AddPreFilter(rootPre).AddPostFilter(rootPost)
+	posts.AddPreFilter(postsPre)
+	detail.AddPostFilter(detailPost)
+
+	var routes = Routes(root)
+	if len(routes) != 2 {
+		t.Fatal("路由数量错误", len(routes))
+	}
+	var r = routes[0]
+	if r.Router != detail || r.Pattern() != `/Posts/p{id=\d+}.html` || len(r.Keys) != 1 || r.Keys[0] != "id" {
+		t.Fatal("正则路由信息错误", r.Pattern(), r.Keys)
+	}
+	if len(r.PreFilters) != 2 || r.PreFilters[0] != rootPre || r.PreFilters[1] != postsPre {
+		t.Fatal("前置过滤器顺序错误", r.PreFilters)
+	}
+	if len(r.PostFilters) != 2 || r.PostFilters[0] != detailPost || r.PostFilters[1] != rootPost {
+		t.Fatal("后置过滤器顺序错误", r.PostFilters)
+	}
+	if routes[1].Router != files || routes[1].Pattern() != "/Posts/*" {
+		t.Fatal("无限路由信息错误", routes[1].Pattern())
+	}
+}
+
+func TestTypedParams(t *testing.T) {
+	var root, _ = NewRouter("base", "", nil)
+	var item, _ = NewRouter("base", "item", "{id:uuid}")
+	var files, _ = NewRouter("base", "files", "{rest:path}")
+	var get, _ = NewRouter("base", "Get", nil)
+	get.SetRouterExcutorGenerator(func() RouterExcutor {
+		return &TestBaseExcutor{}
+	})
+	root.AddChild(item)
+	root.AddChild(files)
+	files.AddChild(get)
+
+	var context = NewBaseContext("/a/b/c.txt/Get")
+	var r, ok = root.Find(context)
+	if !ok || r != get {
+		t.Fatal("多段路由匹配失败")
+	}
+	var rest, _ = context.Param("rest")
+	if rest != "a/b/c.txt" {
+		t.Fatal("多段路由参数错误", rest)
+	}
+	context = NewBaseContext("/0f8fad5b-d9cb-469f-a165-70867728950e")
+	r, ok = root.Find(context)
+	if !ok || r != item {
+		t.Fatal("uuid路由匹配失败")
+	}
+	var id, _ = context.Value("id")
+	if id != "0f8fad5b-d9cb-469f-a165-70867728950e" {
+		t.Fatal("uuid路由值错误", id)
+	}
+	url, err := BuildURL(get, map[string]string{"rest": "a b/c.txt"})
+	if err != nil || url != "/a%20b/c.txt/Get" {
+		t.Fatal("多段路由url生成错误", url, err)
+	}
+}
@@ -6,12 +6,18 @@ import "sync"
 type Router interface {
 	// Name 返回当前路由名称
 	Name() string
+	// Alias 返回当前路由别名,别名用于查找路由并生成url
+	Alias() string
+	// SetAlias 设置当前路由别名
+	SetAlias(alias string) Router
 	// MatchString 返回当前路由用于进行匹配的字符串
 	MatchString() string
 	// Parent 返回当前父路由,每个Router只能有一个Parent
 	Parent() Router
 	// SetParent 设置当前路由父路由,当前路由必须是父路由的子路由
 	SetParent(router Router) error
+	// Keys 返回当前路由能够提取的路由值名称
+	Keys() []string
 	// Normal 返回当前路由是否为通常路由,通常路由可以使用MatchString()返回的字符串进行直接匹配
 	Normal() bool
 	// AddChild 添加子路由,Name相同的路由自动合并
@@ -28,14 +34,24 @@ type Router interface {
 	AddPreFilter(filter PreFilter) Router
 	// RemovePreFilter 移除前置过滤器
 	RemovePreFilter(filter PreFilter) bool
+	// PreFilters 返回当前路由的前置过滤器
+	PreFilters() []PreFilter
 	// ExecPreFilter 执行前置过滤器
 	ExecPreFilter(context RouterContext) bool
 	// AddPostFilter 添加后置过滤器
 	AddPostFilter(filter PostFilter) Router
 	// RemovePostFilter 移除后置过滤器
 	RemovePostFilter(filter PostFilter) bool
+	// PostFilters 返回当前路由的后置过滤器
+	PostFilters() []PostFilter
 	// ExecPostFilter 执行后置过滤器
 	ExecPostFilter(context RouterContext, result interface{}) bool
+	// AddAroundFilter 添加环绕过滤器
+	AddAroundFilter(filter AroundFilter) Router
+	// RemoveAroundFilter 移除环绕过滤器
+	RemoveAroundFilter(filter AroundFilter) bool
+	// AroundFilters 返回当前路由的环绕过滤器
+	AroundFilters() []AroundFilter
 	// SetRouterExcutorGenerator 设置路由执行器生成方法
 	SetRouterExcutorGenerator(RouterExcutorGenerator)
 	// RouterExcutor 获得路由执行器
@@ -46,6 +62,14 @@ type Router interface {
 	Match(context RouterContext) (RouterExcutor, bool)
 }
 
+// 需要在子孙路由发生变化时得到通知的路由,例如缓存了匹配结构的树路由
+type invalidator interface {
+	// check 检查即将成为同一级的路由是否允许添加,返回错误时不添加路由
+	check(children []Router) error
+	// invalidate 自身或子孙路由r的子路由发生变化后调用,r的子路由不合法时panic
+	invalidate(r Router)
+}
+
 // 路由执行器生成器,每次应当返回一个全新的RouterExcutor实例
 type RouterExcutorGenerator func() RouterExcutor
 
@@ -65,6 +89,10 @@ type RouterContext interface {
 	Value(name string) (string, bool)
 	// SetValue 设置路由值
 	SetValue(name string, value string)
+	// Param 返回解析后的路由参数,{key:类型}形式的参数为相应类型的值,其他参数为字符串
+	Param(name string) (interface{}, bool)
+	// SetParam 设置解析后的路由参数
+	SetParam(name string, value interface{})
 }
 
 // 路由执行器
@@ -95,6 +123,17 @@ type PostFilter interface {
 	Filter(context RouterContext, result interface{}) bool
 }
 
+// 环绕过滤器的后续处理方法,执行后续的环绕过滤器,前置过滤器,处理方法和后置过滤器
+type AroundNext func() (interface{}, error)
+
+// 环绕过滤器,包装整个执行过程,可以测量执行时间,恢复panic或者直接返回自定义的结果
+type AroundFilter interface {
+	// Around 过滤该请求
+	//  next:后续处理方法,不调用next时后续的过滤器和处理方法都不会执行
+	//  return:作为执行结果返回,可以是next的返回值,也可以是自定义的结果
+	Around(context RouterContext, next AroundNext) (interface{}, error)
+}
+
 // 路由创建器
 //  name: 路由名称
 //  match: 用于进行匹配的内容,必须是指定路由所需要的内容
@@ -0,0 +1,336 @@
+package router
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// 树路由,将全部子路由编译为按路由段匹配的前缀树,匹配顺序与添加顺序无关
+//  匹配优先级:常规路由 > 单路由段的正则路由(包括类型参数) > 其他类型的路由 > 多路由段的正则路由或无限路由
+//  同一级的单路由段正则路由按普通字符串的总长度(由长到短),未指定正则表达式的key的数量(由少到多),正则表达式和名称排序,匹配正则表达式之前先检查普通字符串前缀和后缀
+//  添加路由前检查同一级的子路由是否冲突(相同的常规匹配字符串,相同的正则表达式,多个多路由段的正则路由或无限路由),冲突时panic并且不添加该路由
+//  路由发生变化后前缀树在下一次匹配时重新编译,嵌套的树路由使用自身的前缀树进行匹配
+type TreeRouter struct {
+	BaseRouter
+	root atomic.Pointer[treeNode] //编译后的前缀树,路由发生变化后为nil
+	mu   sync.Mutex               //保证前缀树只编译一次
+}
+
+// NewTreeRouter 创建树路由,参数与NewBaseRouter相同
+func NewTreeRouter(name string, match interface{}) (Router, error) {
+	var base, err = newBaseRouter(name, match)
+	if err != nil {
+		return nil, err
+	}
+	var r = &TreeRouter{BaseRouter: *base}
+	r.self = r
+	return r, nil
+}
+
+// AddChild 添加子路由,子路由冲突时panic并且不添加该路由
+func (this *TreeRouter) AddChild(router Router) {
+	this.BaseRouter.AddChild(router)
+	this.invalidate(this)
+}
+
+// AddChildren 批量添加子路由,子路由冲突时panic
+func (this *TreeRouter) AddChildren(routers []Router) {
+	for _, v := range routers {
+		this.AddChild(v)
+	}
+}
+
+// RemoveChild 移除指定名称的路由,并返回该路由
+func (this *TreeRouter) RemoveChild(name string) (Router, bool) {
+	var r, ok = this.BaseRouter.RemoveChild(name)
+	if ok {
+		this.invalidate(this)
+	}
+	return r, ok
+}
+
+// Compile 编译前缀树,通常在第一次匹配时自动编译
+func (this *TreeRouter) Compile() error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	var root, err = compileTreeNode(this)
+	if err != nil {
+		return err
+	}
+	this.root.Store(root)
+	return nil
+}
+
+// check 检查同一级的路由是否冲突
+func (this *TreeRouter) check(children []Router) error {
+	return checkChildren(children)
+}
+
+// invalidate 检查r的子路由是否冲突,并在下一次匹配时重新编译前缀树,子路由冲突时panic
+func (this *TreeRouter) invalidate(r Router) {
+	var err = checkConflict(r)
+	if err != nil {
+		panic(err)
+	}
+	this.root.Store(nil)
+}
+
+// tree 返回编译后的前缀树
+func (this *TreeRouter) tree() *treeNode {
+	var root = this.root.Load()
+	if root == nil {
+		var err = this.Compile()
+		if err != nil {
+			panic(err)
+		}
+		root = this.root.Load()
+	}
+	return root
+}
+
+// Find 查找路由,该路由不一定能够生成RouterExcutor
+func (this *TreeRouter) Find(context RouterContext) (Router, bool) {
+	if this.reg && this.segment.Multi {
+		return this.BaseRouter.Find(context)
+	}
+	var segs = context.Segments()
+	if len(segs) <= 0 {
+		return nil, false
+	}
+	var raws []string
+	var values []interface{}
+	if this.reg {
+		var ok bool
+		raws, values, ok = this.segment.Match(segs[0])
+		if !ok {
+			return nil, false
+		}
+	} else if context.Matched() == 0 && this.unify(segs[0]) != this.unify(this.match) {
+		//当前是常规根路由,则进行路由名称匹配
+		return nil, false
+	}
+	context.Match(1)
+	var router, ok = this.tree().find(context)
+	if !ok {
+		context.Unmatch(1)
+		return nil, false
+	}
+	setValues(context, this.keys, raws, values)
+	return router, true
+}
+
+// 前缀树节点
+type treeNode struct {
+	router    Router               //节点对应的路由
+	exact     map[string]*treeNode //常规子路由,使用匹配字符串作为key
+	static    map[string]*treeNode //常规子路由,使用小写的匹配字符串作为key
+	params    []*treeNode          //单路由段的正则子路由,按匹配优先级排序
+	others    []Router             //其他类型的子路由,使用子路由自身的Find进行匹配
+	multi     *treeNode            //多路由段的正则子路由
+	unlimited Router               //无限子路由
+	segment   *RegSegment          //正则段,常规路由为nil
+	keys      []string             //可提取keys
+	prefix    string               //正则段的普通字符串前缀
+	suffix    string               //正则段的普通字符串后缀
+}
+
+// baseOf 返回基本路由或树路由中的基本路由,其他类型的路由返回nil
+func baseOf(r Router) *BaseRouter {
+	switch v := r.(type) {
+	case *BaseRouter:
+		return v
+	case *TreeRouter:
+		return &v.BaseRouter
+	}
+	return nil
+}
+
+// compileTreeNode 将路由r及其子路由编译为前缀树节点
+func compileTreeNode(r Router) (*treeNode, error) {
+	var err = checkConflict(r)
+	if err != nil {
+		return nil, err
+	}
+	var node = &treeNode{
+		router: r,
+		exact:  make(map[string]*treeNode),
+		static: make(map[string]*treeNode),
+		params: make([]*treeNode, 0),
+		others: make([]Router, 0),
+	}
+	var base = baseOf(r)
+	if base != nil && base.reg {
+		node.segment = base.segment
+		node.keys = base.keys
+		node.prefix, node.suffix = base.segment.literalAffix()
+	}
+	for _, child := range r.Children() {
+		if isUnlimited(child) {
+			node.unlimited = child
+			continue
+		}
+		var b, ok = child.(*BaseRouter)
+		if !ok {
+			node.others = append(node.others, child)
+			continue
+		}
+		var childNode, err = compileTreeNode(b)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case !b.reg:
+			node.exact[b.match] = childNode
+			node.static[strings.ToLower(b.match)] = childNode
+		case b.segment.Multi:
+			node.multi = childNode
+		default:
+			node.params = append(node.params, childNode)
+		}
+	}
+	sort.Slice(node.params, func(i, j int) bool {
+		var a, b = node.params[i], node.params[j]
+		var la, lb = len(strings.Join(a.segment.texts, "")), len(strings.Join(b.segment.texts, ""))
+		if la != lb {
+			return la > lb
+		}
+		var wa, wb = a.segment.wildcards(), b.segment.wildcards()
+		if wa != wb {
+			return wa < wb
+		}
+		if a.segment.Exp != b.segment.Exp {
+			return a.segment.Exp < b.segment.Exp
+		}
+		return a.router.Name() < b.router.Name()
+	})
+	return node, nil
+}
+
+// checkConflict 检查r的子路由是否冲突
+func checkConflict(r Router) error {
+	return checkChildren(r.Children())
+}
+
+// checkChildren 检查同一级的路由是否冲突
+//  冲突:相同的常规匹配字符串(不区分大小写),相同的正则表达式,多个多路由段的正则路由或无限路由
+func checkChildren(children []Router) error {
+	var statics = make(map[string]Router)
+	var exps = make(map[string]Router)
+	var catchAll Router
+	for _, child := range children {
+		var exist Router
+		var b = baseOf(child)
+		switch {
+		case isUnlimited(child) || (b != nil && b.reg && b.segment.Multi):
+			exist = catchAll
+			catchAll = child
+		case b != nil && !b.reg:
+			var key = strings.ToLower(b.match)
+			exist = statics[key]
+			statics[key] = child
+		case b != nil:
+			exist = exps[b.segment.Exp]
+			exps[b.segment.Exp] = child
+		}
+		if exist != nil {
+			return ErrorRouteConflict.Format(routerPath(exist), routerPath(child)).Error()
+		}
+	}
+	return nil
+}
+
+// accept 检查路由段是否包含正则段的普通字符串前缀和后缀
+func (this *treeNode) accept(seg string) bool {
+	return len(seg) >= len(this.prefix)+len(this.suffix) && strings.HasPrefix(seg, this.prefix) && strings.HasSuffix(seg, this.suffix)
+}
+
+// find 当前节点已经匹配,使用子节点匹配剩余的路由段
+func (this *treeNode) find(context RouterContext) (Router, bool) {
+	var segs = context.Segments()
+	if len(segs) <= 0 {
+		return this.router, true
+	}
+	var seg = segs[0]
+	//常规子路由
+	var child, ok = this.exact[seg]
+	if !ok {
+		child, ok = this.static[strings.ToLower(seg)]
+	}
+	if ok {
+		context.Match(1)
+		var r, found = child.find(context)
+		if found {
+			return r, true
+		}
+		context.Unmatch(1)
+	}
+	//单路由段的正则子路由
+	for _, p := range this.params {
+		if !p.accept(seg) {
+			continue
+		}
+		var raws, values, ok = p.segment.Match(seg)
+		if !ok {
+			continue
+		}
+		context.Match(1)
+		var r, found = p.find(context)
+		if found {
+			setValues(context, p.keys, raws, values)
+			return r, true
+		}
+		context.Unmatch(1)
+	}
+	//其他类型的子路由
+	for _, o := range this.others {
+		var r, found = o.Find(context)
+		if found {
+			return r, true
+		}
+	}
+	//多路由段的正则子路由,优先匹配更多的路由段,剩余的路由段必须能够被子路由匹配,最后尝试匹配全部路由段
+	if this.multi != nil {
+		var m = this.multi
+		for count := len(segs) - 1; count >= 0; count-- {
+			var n = count
+			if n == 0 {
+				n = len(segs)
+			}
+			var raws, values, ok = m.segment.Match(strings.Join(segs[:n], "/"))
+			if !ok {
+				continue
+			}
+			context.Match(n)
+			var r, found = m.find(context)
+			if found {
+				setValues(context, m.keys, raws, values)
+				return r, true
+			}
+			context.Unmatch(n)
+		}
+	}
+	//无限子路由
+	if this.unlimited != nil {
+		return this.unlimited.Find(context)
+	}
+	return nil, false
+}
+
+// setValues 设置路由值和解析后的路由参数
+func setValues(context RouterContext, keys []string, raws []string, values []interface{}) {
+	for i, v := range raws {
+		context.SetValue(keys[i], v)
+		context.SetParam(keys[i], values[i])
+	}
+}
+
+// routerPath 返回从根路由到r的路径模式
+func routerPath(r Router) string {
+	var chain = make([]Router, 0)
+	for ; r != nil; r = r.Parent() {
+		chain = append([]Router{r}, chain...)
+	}
+	return Pattern(chain)
+}
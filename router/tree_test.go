@@ -0,0 +1,176 @@
+package router
+
+import (
+	"fmt"
+	"testing"
+)
+
+// newTestRouter 创建带有Get执行器的路由
+func newTestRouter(kind string, name string, match interface{}) Router {
+	var r, err = NewRouter(kind, name, match)
+	if err != nil {
+		panic(err)
+	}
+	var get, _ = NewRouter("base", "Get", nil)
+	get.SetRouterExcutorGenerator(func() RouterExcutor {
+		return &TestBaseExcutor{}
+	})
+	r.AddChild(get)
+	return r
+}
+
+// findName 查找路由并返回Get路由的上级路由名称
+func findName(root Router, path string) (string, *BaseContext) {
+	var context = NewBaseContext(path)
+	var r, ok = root.Find(context)
+	if !ok || r.Parent() == nil {
+		return "", context
+	}
+	return r.Parent().Name(), context
+}
+
+func TestTreeRouterPrecedence(t *testing.T) {
+	var root, _ = NewRouter("tree", "", nil)
+	root.AddChild(newTestRouter("base", "new", nil))
+	root.AddChild(newTestRouter("base", "name", "{name}"))
+	root.AddChild(newTestRouter("base", "alpha", "{slug:alpha}"))
+	root.AddChild(newTestRouter("base", "int", "{id:int}"))
+	root.AddChild(newTestRouter("base", "page", "p{id:int}.html"))
+	root.AddChild(newTestRouter("base", "rest", "{rest:path}"))
+	var cases = map[string]string{
+		"/new/Get":          "new",
+		"/NEW/Get":          "new",
+		"/12/Get":           "int",
+		"/abc/Get":          "alpha",
+		"/a-b/Get":          "name",
+		"/p3.html/Get":      "page",
+		"/a/b/Get":          "rest",
+		"/12/unknown/x/Get": "rest",
+	}
+	for path, name := range cases {
+		var found, _ = findName(root, path)
+		if found != name {
+			t.Errorf("%s: expected %s, got %s", path, name, found)
+		}
+	}
+	var _, context = findName(root, "/p3.html/Get")
+	var id, _ = context.Param("id")
+	if id != int64(3) {
+		t.Fatal("路由参数错误", id)
+	}
+}
+
+func TestTreeRouterConflict(t *testing.T) {
+	var conflicts = []func() []Router{
+		func() []Router {
+			return []Router{newTestRouter("base", "a", "{id:int}"), newTestRouter("base", "b", "{id:int}")}
+		},
+		func() []Router {
+			return []Router{newTestRouter("base", "a", "{rest:path}"), newTestRouter("unlimited", "b", nil)}
+		},
+		func() []Router {
+			return []Router{newTestRouter("base", "a", "home"), newTestRouter("base", "b", "Home")}
+		},
+	}
+	for i, create := range conflicts {
+		var root, _ = NewRouter("tree", "", nil)
+		var posts, _ = NewRouter("base", "posts", nil)
+		root.AddChild(posts)
+		for _, parent := range []Router{root, posts} {
+			var routers = create()
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%d: expected conflict", i)
+					}
+				}()
+				parent.AddChildren(routers)
+			}()
+			//冲突的路由没有被添加
+			var _, ok = parent.Child(routers[1].Name())
+			if !ok {
+				continue
+			}
+			t.Errorf("%d: conflicting router %s was added to %s", i, routers[1].Name(), parent.Name())
+		}
+		if len(root.Children()) != 2 || len(posts.Children()) != 1 {
+			t.Errorf("%d: tree changed after conflict: %d, %d", i, len(root.Children()), len(posts.Children()))
+		}
+		var err = checkConflict(root)
+		if err == nil {
+			err = checkConflict(posts)
+		}
+		if err != nil {
+			t.Errorf("%d: tree contains conflicts after panic: %s", i, err)
+		}
+	}
+}
+
+func TestTreeRouterRebuild(t *testing.T) {
+	var root, _ = NewRouter("tree", "", nil)
+	var posts, _ = NewRouter("base", "posts", nil)
+	root.AddChild(posts)
+	posts.AddChild(newTestRouter("base", "detail", "{id:int}"))
+	var found, _ = findName(root, "/posts/5/Get")
+	if found != "detail" {
+		t.Fatal("添加到树路由之后的子路由无法匹配", found)
+	}
+	posts.RemoveChild("detail")
+	found, _ = findName(root, "/posts/5/Get")
+	if found != "" {
+		t.Fatal("移除的子路由仍然可以匹配", found)
+	}
+}
+
+func TestTreeRouterSameAsBase(t *testing.T) {
+	var base = benchmarkRoutes("base", 5)
+	var tree = benchmarkRoutes("tree", 5)
+	var paths = []string{"/s4/page19/Get", "/s2/item19_123.html/Get", "/s0/item1_-5.html/Get", "/s3/item1_x.html/Get", "/s3/none/Get", "/S1/PAGE3/Get"}
+	for _, path := range paths {
+		var b, bc = findName(base, path)
+		var r, tc = findName(tree, path)
+		var bid, _ = bc.Param("id")
+		var tid, _ = tc.Param("id")
+		if b != r || bid != tid {
+			t.Errorf("%s: base %s(%v), tree %s(%v)", path, b, bid, r, tid)
+		}
+	}
+}
+
+// benchmarkRoutes 创建包含sections个空间的路由树,每个空间包含20个常规路由和20个正则路由
+func benchmarkRoutes(kind string, sections int) Router {
+	var root, _ = NewRouter(kind, "", nil)
+	for i := 0; i < sections; i++ {
+		var section, _ = NewRouter("base", fmt.Sprintf("s%d", i), nil)
+		for j := 0; j < 20; j++ {
+			section.AddChild(newTestRouter("base", fmt.Sprintf("page%d", j), nil))
+			section.AddChild(newTestRouter("base", fmt.Sprintf("item%d", j), fmt.Sprintf("item%d_{id:int}.html", j)))
+		}
+		root.AddChild(section)
+	}
+	return root
+}
+
+func benchmarkFind(b *testing.B, kind string) {
+	var root = benchmarkRoutes(kind, 100)
+	var paths = []string{"/s99/page19/Get", "/s50/item19_123.html/Get", "/s7/item0_1.html/Get", "/s3/none/Get"}
+	var contexts = make([]*BaseContext, len(paths))
+	for i, p := range paths {
+		contexts[i] = NewBaseContext(p)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var context = contexts[i%len(contexts)]
+		context.Level = 0
+		root.Find(context)
+	}
+}
+
+func BenchmarkBaseRouterFind(b *testing.B) {
+	benchmarkFind(b, "base")
+}
+
+func BenchmarkTreeRouterFind(b *testing.B) {
+	benchmarkFind(b, "tree")
+}
@@ -1,12 +1,19 @@
 package router
 
+import (
+	"net/url"
+	"strings"
+)
+
 // 无限路由
 type UnlimitedRouter struct {
 	self              Router                 //路由自身(所有继承当前路由的路由在创建时需要设置self=this)
 	parent            Router                 //父路由
 	name              string                 //当前路由名称
+	alias             string                 //当前路由别名
 	preFilters        []PreFilter            //在子路由处理之前执行的过滤器
 	postFilters       []PostFilter           //在子路由处理之后执行的过滤器
+	aroundFilters     []AroundFilter         //包装子路由处理过程的过滤器
 	executorGenerator RouterExcutorGenerator //路由执行器生成器
 }
 
@@ -18,6 +25,7 @@ func NewUnlimitedRouter(name string, match interface{}) (Router, error) {
 	r.name = name
 	r.preFilters = make([]PreFilter, 0)
 	r.postFilters = make([]PostFilter, 0)
+	r.aroundFilters = make([]AroundFilter, 0)
 	r.self = r
 	return r, nil
 }
@@ -27,6 +35,27 @@ func (this *UnlimitedRouter) Name() string {
 	return this.name
 }
 
+// Alias 返回当前路由别名,别名用于查找路由并生成url
+func (this *UnlimitedRouter) Alias() string {
+	return this.alias
+}
+
+// SetAlias 设置当前路由别名
+func (this *UnlimitedRouter) SetAlias(alias string) Router {
+	this.alias = alias
+	return this.self
+}
+
+// BuildSegment 生成无限路由匹配的路由段,使用values中与路由名称相同的值,该值可以包含/
+func (this *UnlimitedRouter) BuildSegment(values map[string]string) (string, []string, error) {
+	var value = strings.Trim(values[this.name], "/")
+	var segs = strings.Split(value, "/")
+	for i, s := range segs {
+		segs[i] = url.PathEscape(s)
+	}
+	return strings.Join(segs, "/"), []string{this.name}, nil
+}
+
 // MatchString 返回当前路由用于进行匹配的字符串
 func (this *UnlimitedRouter) MatchString() string {
 	return ""
@@ -40,8 +69,7 @@ func (this *UnlimitedRouter) Parent() Router {
 // SetParent 设置当前路由父路由,当前路由必须是父路由的子路由
 func (this *UnlimitedRouter) SetParent(router Router) error {
 	var r, ok = router.Child(this.name)
-	var x, ok2 = r.(*UnlimitedRouter)
-	if ok && ok2 && x == this {
+	if ok && r == this.self {
 		if this.parent != nil && this.parent != router {
 			this.parent.RemoveChild(this.name)
 		}
@@ -51,6 +79,11 @@ func (this *UnlimitedRouter) SetParent(router Router) error {
 	return ErrorInvalidParentRouter.Error()
 }
 
+// Keys 无限路由不提取路由值
+func (this *UnlimitedRouter) Keys() []string {
+	return []string{}
+}
+
 // Normal 返回当前路由是否为通常路由,通常路由可以使用MatchString()返回的字符串进行直接匹配
 func (this *UnlimitedRouter) Normal() bool {
 	return false
@@ -100,6 +133,11 @@ func (this *UnlimitedRouter) RemovePreFilter(filter PreFilter) bool {
 	return false
 }
 
+// PreFilters 返回当前路由的前置过滤器
+func (this *UnlimitedRouter) PreFilters() []PreFilter {
+	return append([]PreFilter{}, this.preFilters...)
+}
+
 // ExecPreFilter 执行前置过滤器
 func (this *UnlimitedRouter) ExecPreFilter(context RouterContext) bool {
 	for _, router := range this.preFilters {
@@ -130,6 +168,11 @@ func (this *UnlimitedRouter) RemovePostFilter(filter PostFilter) bool {
 	return false
 }
 
+// PostFilters 返回当前路由的后置过滤器
+func (this *UnlimitedRouter) PostFilters() []PostFilter {
+	return append([]PostFilter{}, this.postFilters...)
+}
+
 // ExecPostFilter 执行后置过滤器
 func (this *UnlimitedRouter) ExecPostFilter(context RouterContext, result interface{}) bool {
 	for _, router := range this.postFilters {
@@ -141,6 +184,30 @@ func (this *UnlimitedRouter) ExecPostFilter(context RouterContext, result interf
 	return true
 }
 
+// AddAroundFilter 添加环绕过滤器
+func (this *UnlimitedRouter) AddAroundFilter(filter AroundFilter) Router {
+	if filter != nil {
+		this.aroundFilters = append(this.aroundFilters, filter)
+	}
+	return this.self
+}
+
+// RemoveAroundFilter 移除环绕过滤器
+func (this *UnlimitedRouter) RemoveAroundFilter(filter AroundFilter) bool {
+	for index, child := range this.aroundFilters {
+		if child == filter {
+			this.aroundFilters = append(this.aroundFilters[:index], this.aroundFilters[index+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// AroundFilters 返回当前路由的环绕过滤器
+func (this *UnlimitedRouter) AroundFilters() []AroundFilter {
+	return append([]AroundFilter{}, this.aroundFilters...)
+}
+
 // SetRouterExcutor 设置路由执行器生成方法
 func (this *UnlimitedRouter) SetRouterExcutorGenerator(reg RouterExcutorGenerator) {
 	this.executorGenerator = reg
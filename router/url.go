@@ -0,0 +1,80 @@
+package router
+
+import (
+	"net/url"
+	"strings"
+)
+
+// 可以生成路由段的路由
+type SegmentBuilder interface {
+	// BuildSegment 使用values生成能够匹配当前路由的路由段
+	//  return:(路由段,使用的路由值名称,错误)
+	BuildSegment(values map[string]string) (string, []string, error)
+}
+
+// FindAlias 在root及其子路由中查找指定别名的路由,别名必须唯一
+func FindAlias(root Router, alias string) (Router, error) {
+	var result Router
+	var count = 0
+	var find func(r Router)
+	find = func(r Router) {
+		if r.Alias() == alias {
+			result = r
+			count++
+		}
+		for _, child := range r.Children() {
+			find(child)
+		}
+	}
+	find(root)
+	if count <= 0 {
+		return nil, ErrorAliasNotFound.Format(alias).Error()
+	}
+	if count > 1 {
+		return nil, ErrorDuplicateAlias.Format(alias).Error()
+	}
+	return result, nil
+}
+
+// BuildURL 生成能够匹配路由r的url,路由段依次由根路由到r生成
+//  values:路由值,正则路由使用的值必须完全匹配相应的正则表达式,其余的值作为查询参数
+func BuildURL(r Router, values map[string]string) (string, error) {
+	var routers = make([]Router, 0)
+	for ; r != nil; r = r.Parent() {
+		routers = append(routers, r)
+	}
+	var segs = make([]string, len(routers))
+	var used = make(map[string]bool)
+	for i, r := range routers {
+		var builder, ok = r.(SegmentBuilder)
+		if ok {
+			var seg, keys, err = builder.BuildSegment(values)
+			if err != nil {
+				return "", err
+			}
+			for _, k := range keys {
+				used[k] = true
+			}
+			segs[len(routers)-1-i] = seg
+		} else if r.Normal() {
+			segs[len(routers)-1-i] = url.PathEscape(r.MatchString())
+		} else {
+			return "", ErrorInvalidSegment.Format(r.Name(), r.MatchString()).Error()
+		}
+	}
+	var path = strings.Join(segs, "/")
+	if len(segs) == 1 && path == "" {
+		//根路由
+		path = "/"
+	}
+	var query = url.Values{}
+	for k, v := range values {
+		if !used[k] {
+			query.Set(k, v)
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path, nil
+}
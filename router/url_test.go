@@ -0,0 +1,72 @@
+package router
+
+import "testing"
+
+func TestBuildURL(t *testing.T) {
+	var root, _ = NewRouter("base", "", nil)
+	var posts, _ = NewRouter("base", "Posts", nil)
+	var detail, _ = NewRouter("base", "detail", `p{id=\d+}_{slug=[a-z-]+}.html`)
+	var files, _ = NewRouter("unlimited", "files", nil)
+	root.AddChild(posts)
+	posts.AddChild(detail.SetAlias("post"))
+	posts.AddChild(files.SetAlias("files"))
+	root.SetAlias("home")
+
+	var r, err = FindAlias(root, "post")
+	if err != nil || r != detail {
+		t.Fatal("路由别名查找错误", err)
+	}
+	url, err := BuildURL(r, map[string]string{"id": "12", "slug": "hello-world", "page": "2"})
+	if err != nil || url != "/Posts/p12_hello-world.html?page=2" {
+		t.Fatal("url生成错误", url, err)
+	}
+	//生成的url能够匹配原路由
+	var context = &TestContext{*NewBaseContext(url[:len(url)-7]), map[string]string{}}
+	found, ok := root.Find(context)
+	if !ok || found != detail || context.values["id"] != "12" || context.values["slug"] != "hello-world" {
+		t.Fatal("生成的url无法匹配路由", context.values)
+	}
+	_, err = BuildURL(r, map[string]string{"id": "abc", "slug": "x"})
+	if err == nil {
+		t.Fatal("不匹配正则表达式的值应当返回错误")
+	}
+	_, err = BuildURL(r, map[string]string{"id": "1"})
+	if err == nil {
+		t.Fatal("缺少路由值应当返回错误")
+	}
+	url, err = BuildURL(files, map[string]string{"files": "a b/c.txt"})
+	if err != nil || url != "/Posts/a%20b/c.txt" {
+		t.Fatal("无限路由url生成错误", url, err)
+	}
+	url, err = BuildURL(root, nil)
+	if err != nil || url != "/" {
+		t.Fatal("根路由url生成错误", url, err)
+	}
+	_, err = FindAlias(root, "none")
+	if err == nil {
+		t.Fatal("不存在的别名应当返回错误")
+	}
+	files.SetAlias("post")
+	_, err = FindAlias(root, "post")
+	if err == nil {
+		t.Fatal("重复的别名应当返回错误")
+	}
+}
+
+func TestRegSegmentBuild(t *testing.T) {
+	var segment, err = ParseReg("list{page}_{number=[0-9]+}x")
+	if err != nil || segment.Exp != "^list(.*)_([0-9]+)x$" {
+		t.Fatal(err, "解析错误")
+	}
+	str, err := segment.Build(map[string]string{"page": "a", "number": "10"})
+	if err != nil || str != "lista_10x" {
+		t.Fatal(err, "生成错误", str)
+	}
+	if len(segment.regs) != 2 || segment.regs[1].String() != "^(?:[0-9]+)$" {
+		t.Fatal("解析时应当编译每个key的正则表达式")
+	}
+	_, err = segment.Build(map[string]string{"page": "a", "number": "1a"})
+	if err == nil {
+		t.Fatal("不匹配正则表达式的值应当返回错误")
+	}
+}
@@ -126,6 +126,7 @@ type MemSessionContainer struct {
 	defaultExpire  int                    //默认过期时间
 	rwm            sync.RWMutex           //读写锁
 	closed         bool                   //是否关闭
+	done           chan struct{}          //关闭后通知清理协程退出
 }
 
 // NewMemSessionContainer 创建Session提供器(数据存储在内存中,source参数无效)
@@ -134,6 +135,7 @@ func NewMemSessionContainer(expire int, source string) (SessionContainer, error)
 	container.sessions = make(map[string]*MemSession, 100)
 	container.defaultExpire = expire
 	container.closed = false
+	container.done = make(chan struct{})
 	go func() {
 		//Clean [60,expire/2]
 		//每分钟检查一次,达到指定时间时清理一次Session并计算下一次清理的时间
@@ -146,7 +148,11 @@ func NewMemSessionContainer(expire int, source string) (SessionContainer, error)
 		var cleanSep = minCleanSep
 		var passTime = time.Duration(0)
 		for !container.closed {
-			time.Sleep(minCleanSep)
+			select {
+			case <-time.After(minCleanSep):
+			case <-container.done:
+				return
+			}
 			passTime += minCleanSep
 			if !container.closed && passTime >= cleanSep {
 				var dead = container.Clean()
@@ -226,7 +232,13 @@ func (this *MemSessionContainer) Clean() int {
 
 // Close 关闭SessionProvider,关闭之后将无法使用
 func (this *MemSessionContainer) Close() {
-	this.closed = true
+	this.rwm.Lock()
+	defer this.rwm.Unlock()
+	if !this.closed {
+		this.closed = true
+		this.sessions = make(map[string]*MemSession)
+		close(this.done)
+	}
 }
 
 // Closed 确认当前SessionProvider是否已经关闭
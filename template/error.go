@@ -27,4 +27,6 @@ func (this Error) String() string {
 const (
 	ErrorParamMustBeFunc    Error = "ErrorParamMustBeFunc(T10010):参数必须是函数"
 	ErrorInvalidPartialView Error = "ErrorInvalidPartialView(T10020):无效的部分视图(%s),找不到指定名称(%s)的模板"
+	ErrorNoURLBuilder       Error = "ErrorNoURLBuilder(T10030):没有设置url生成方法"
+	ErrorInvalidURLParams   Error = "ErrorInvalidURLParams(T10031):路由(%s)的参数必须是name,value形式"
 )
@@ -1,6 +1,7 @@
 package template
 
 import (
+	"fmt"
 	"html/template"
 	"reflect"
 )
@@ -8,6 +9,22 @@ import (
 // 公共模版方法
 var commonFuncMap template.FuncMap
 
+// urlFor 根据路由别名生成url,用于urlfor模板函数
+//  params:路由值,必须是name,value,name,value...的形式
+func (this *ViewTemplates) urlFor(alias string, params ...interface{}) (string, error) {
+	if this.urlBuilder == nil {
+		return "", ErrorNoURLBuilder.Error()
+	}
+	if len(params)%2 != 0 {
+		return "", ErrorInvalidURLParams.Format(alias).Error()
+	}
+	var values = make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		values[fmt.Sprint(params[i])] = fmt.Sprint(params[i+1])
+	}
+	return this.urlBuilder(alias, values)
+}
+
 // RegisterTemplateFunc 注册模板函数
 //  f:必须是一个函数,并且只能有一个返回值,或者有两个返回值并且第二个返回值为error
 func RegisterTemplateFunc(name string, f interface{}) error {
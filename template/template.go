@@ -13,6 +13,8 @@ type ViewTemplates struct {
 	templates map[string]*template.Template
 	config    *TemplateConfig  //视图配置
 	funcMap   template.FuncMap //模板方法
+	//url生成方法,用于urlfor模板函数
+	urlBuilder func(alias string, params map[string]string) (string, error)
 }
 
 // NewViewTemplates 创建视图模板信息
@@ -22,9 +24,16 @@ func NewViewTemplates(config *TemplateConfig) *ViewTemplates {
 		make(map[string]*template.Template),
 		config,
 		commonFuncMap,
+		nil,
 	}
 }
 
+// SetURLBuilder 设置当前视图模板中urlfor模板函数使用的url生成方法,需要在编译模板之前设置
+//  urlfor(alias string, params ...interface{}) string:根据路由别名生成url,params为name,value形式的路由值
+func (this *ViewTemplates) SetURLBuilder(builder func(alias string, params map[string]string) (string, error)) {
+	this.urlBuilder = builder
+}
+
 // CompileAll 编译所有视图
 func (this *ViewTemplates) CompileAll() error {
 	var templates = make(map[string]*template.Template)
@@ -58,6 +67,8 @@ func (this *ViewTemplates) compile(path string) (*template.Template, error) {
 	if this.funcMap != nil {
 		tmpl.Funcs(this.funcMap)
 	}
+	//urlfor绑定到当前视图模板,不同的视图模板可以使用不同的url生成方法
+	tmpl.Funcs(template.FuncMap{"urlfor": this.urlFor})
 	return tmpl.ParseFiles(pathSlice...)
 }
 
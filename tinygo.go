@@ -1,6 +1,8 @@
 // Package tinygo 实现一个组合式应用管理器
 package tinygo
 
+import "time"
+
 // tinygo App管理器
 var manager, _ = NewManager()
 
@@ -9,6 +11,21 @@ func SetEvent(event ManagerEvent) {
 	manager.Event = event
 }
 
+// SetShutdownTimeout 设置停止全部App的总期限
+func SetShutdownTimeout(timeout time.Duration) {
+	manager.ShutdownTimeout = timeout
+}
+
+// SetDependencies 设置名称为name的App依赖的App名称列表
+func SetDependencies(name string, deps ...string) {
+	manager.SetDependencies(name, deps...)
+}
+
+// SetRestartPolicy 设置名称为name的App的重启策略
+func SetRestartPolicy(name string, policy *RestartPolicy) {
+	manager.SetRestartPolicy(name, policy)
+}
+
 // AddApp 添加App
 func AddApp(app App) {
 	manager.AddApp(app)
@@ -21,7 +38,12 @@ func AddApps(apps ...App) {
 	}
 }
 
-// Run 运行
+// Run 运行,收到SIGINT或SIGTERM信号时停止全部App
 func Run() {
 	manager.Run()
 }
+
+// Stop 停止全部App
+func Stop() {
+	manager.Stop()
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"reflect"
 	"regexp"
+	"strings"
 )
 
 // 验证器函数表
@@ -13,7 +14,14 @@ const sep = ":"
 
 var funcNameReg = regexp.MustCompile("^([a-zA-Z][a-zA-Z0-9]*)?(>|>=|<|<=|==|!=)?$")
 
+// 包含关系运算符的函数名称
+var relopNameReg = regexp.MustCompile("(>|>=|<|<=|==|!=)$")
+
+// 正则函数的名称
+const regexpFuncName = "regexp"
+
 // RegisterFunc 注册验证函数,验证函数的第一个参数必须是string,并且其他参数只能是int64,float64,string三种类型,返回值必须是bool类型
+//  最后一个参数可以是...int64,...float64或...string类型的可变参数,例如in(str string, values ...string)
 func RegisterFunc(name string, f interface{}) error {
 	if !funcNameReg.MatchString(name) {
 		return ErrorInvalidFuncName.Format(name).Error()
@@ -33,16 +41,50 @@ func RegisterFunc(name string, f interface{}) error {
 	var newName = name + sep
 	for i := 1; i < vType.NumIn(); i++ {
 		var t = vType.In(i)
+		var variadic = vType.IsVariadic() && i == vType.NumIn()-1
+		if variadic {
+			t = t.Elem()
+		}
 		var n, ok = CheckType(t.Kind())
 		if !ok {
 			return ErrorIncorrectParamType.Format(i, t.Kind().String()).Error()
 		}
 		newName += n
+		if variadic {
+			newName += variadicMark
+		}
 	}
 	funcs[newName] = &NamedFunc{name, &v}
 	return nil
 }
 
+// 可变参数标记
+const variadicMark = "*"
+
+// findFunc 查找验证函数
+//  name:函数名称
+//  types:参数类型列表,由CheckType返回的类型字符串组成
+//  参数类型完全匹配的函数优先,其次是固定参数最多的可变参数函数
+func findFunc(name string, types string) (ValidatorFunc, bool) {
+	var vf, ok = funcs[name+sep+types]
+	if ok {
+		return vf, true
+	}
+	for i := len(types); i >= 0; i-- {
+		var rest = types[i:]
+		for _, t := range []string{"I", "F", "S"} {
+			if strings.Count(rest, t) != len(rest) {
+				continue
+			}
+			vf, ok = funcs[name+sep+types[:i]+t+variadicMark]
+			if ok {
+				return vf, true
+			}
+		}
+	}
+	return nil, false
+}
+
 // CheckType 检查类型是否符合参数要求
 func CheckType(k reflect.Kind) (string, bool) {
 	switch k {
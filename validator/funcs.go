@@ -1,7 +1,15 @@
 package validator
 
 import (
+	stdbase64 "encoding/base64"
+	stdjson "encoding/json"
+	"net"
+	"net/mail"
+	neturl "net/url"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 	"unicode/utf8"
 )
 
@@ -79,7 +87,7 @@ func neF(str string, l float64) bool {
 	return err == nil && i != l
 }
 
-//字符串比较方法
+// 字符串比较方法
 // eqS == string
 func eqS(str string, l string) bool {
 	return str == l
@@ -151,3 +159,162 @@ func clenEqI(str string, l int64) bool {
 func clenNeI(str string, l int64) bool {
 	return utf8.RuneCountInString(str) != int(l)
 }
+
+// 格式验证方法
+// uuid的格式
+var uuidReg = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+// 数值的格式
+var numericReg = regexp.MustCompile(`^[-+]?[0-9]+(\.[0-9]+)?$`)
+
+// email 电子邮件地址,不能包含显示名称
+func email(str string) bool {
+	var addr, err = mail.ParseAddress(str)
+	return err == nil && addr.Address == str
+}
+
+// url 包含协议和主机的绝对url
+func url(str string) bool {
+	var u, err = neturl.ParseRequestURI(str)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// ip ipv4或ipv6地址
+func ip(str string) bool {
+	return net.ParseIP(str) != nil
+}
+
+// ipv4 ipv4地址
+func ipv4(str string) bool {
+	var addr = net.ParseIP(str)
+	return addr != nil && addr.To4() != nil && !strings.Contains(str, ":")
+}
+
+// ipv6 ipv6地址
+func ipv6(str string) bool {
+	var addr = net.ParseIP(str)
+	return addr != nil && strings.Contains(str, ":")
+}
+
+// cidr CIDR格式的网段,例如192.168.0.0/16
+func cidr(str string) bool {
+	var _, _, err = net.ParseCIDR(str)
+	return err == nil
+}
+
+// uuid 8-4-4-4-12格式的uuid,不区分大小写
+func uuid(str string) bool {
+	return uuidReg.MatchString(str)
+}
+
+// hex 十六进制字符串
+func hex(str string) bool {
+	if str == "" {
+		return false
+	}
+	for _, c := range str {
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
+			return false
+		}
+	}
+	return true
+}
+
+// base64 标准base64编码的字符串
+func base64(str string) bool {
+	var _, err = stdbase64.StdEncoding.DecodeString(str)
+	return str != "" && err == nil
+}
+
+// alpha 只包含英文字母
+func alpha(str string) bool {
+	if str == "" {
+		return false
+	}
+	for _, c := range str {
+		if !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')) {
+			return false
+		}
+	}
+	return true
+}
+
+// alnum 只包含英文字母和数字
+func alnum(str string) bool {
+	if str == "" {
+		return false
+	}
+	for _, c := range str {
+		if !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+// numeric 整数或小数,可以包含正负号
+func numeric(str string) bool {
+	return numericReg.MatchString(str)
+}
+
+// date 使用layout解析的日期时间,layout格式参考time.Parse
+func date(str string, layout string) bool {
+	var _, err = time.Parse(layout, str)
+	return err == nil
+}
+
+// json 有效的json
+func json(str string) bool {
+	return stdjson.Valid([]byte(str))
+}
+
+// 集合验证方法
+// inS 等于values中的一个字符串
+func inS(str string, values ...string) bool {
+	for _, v := range values {
+		if str == v {
+			return true
+		}
+	}
+	return false
+}
+
+// notinS 不等于values中的任何字符串
+func notinS(str string, values ...string) bool {
+	return !inS(str, values...)
+}
+
+// inI 等于values中的一个整数
+func inI(str string, values ...int64) bool {
+	var i, err = strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return false
+	}
+	for _, v := range values {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// notinI 不是整数或者不等于values中的任何整数
+func notinI(str string, values ...int64) bool {
+	return !inI(str, values...)
+}
+
+// 子字符串验证方法
+// prefix 以s开头
+func prefix(str string, s string) bool {
+	return strings.HasPrefix(str, s)
+}
+
+// suffix 以s结尾
+func suffix(str string, s string) bool {
+	return strings.HasSuffix(str, s)
+}
+
+// contains 包含s
+func contains(str string, s string) bool {
+	return strings.Contains(str, s)
+}
@@ -29,5 +29,31 @@ func init() {
 	RegisterFunc("clen==", clenEqI)
 	RegisterFunc("clen!=", clenNeI)
 
+	// 注册格式验证方法
+	RegisterFunc("email", email)
+	RegisterFunc("url", url)
+	RegisterFunc("ip", ip)
+	RegisterFunc("ipv4", ipv4)
+	RegisterFunc("ipv6", ipv6)
+	RegisterFunc("cidr", cidr)
+	RegisterFunc("uuid", uuid)
+	RegisterFunc("hex", hex)
+	RegisterFunc("base64", base64)
+	RegisterFunc("alpha", alpha)
+	RegisterFunc("alnum", alnum)
+	RegisterFunc("numeric", numeric)
+	RegisterFunc("date", date)
+	RegisterFunc("json", json)
+	RegisterFunc("in", inS)
+	RegisterFunc("in", inI)
+	RegisterFunc("notin", notinS)
+	RegisterFunc("notin", notinI)
+	RegisterFunc("prefix", prefix)
+	RegisterFunc("suffix", suffix)
+	RegisterFunc("contains", contains)
+
+	// 注册基础验证器方法的错误信息
+	registerMessages()
+
 	Register("string", NewStringValidator)
 }
@@ -0,0 +1,161 @@
+package validator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 默认语言,指定语言中不存在错误信息模板时使用
+var DefaultLocale = "zh"
+
+var (
+	msgMu    sync.RWMutex                         //错误信息模板互斥锁
+	messages = make(map[string]map[string]string) //错误信息模板,语言->名称->模板
+)
+
+// RegisterMessage 注册指定语言的错误信息模板
+//  locale:语言,例如zh,en,zh-CN,不区分大小写
+//  name:验证函数名称(例如len<=,in,正则函数为regexp,值不存在为required)或者自定义的信息名称
+//  template:错误信息模板,{0},{1}...表示验证函数的参数,{params}表示以逗号分隔的全部参数,{value}表示未通过验证的值,{field}表示字段名称
+func RegisterMessage(locale string, name string, template string) {
+	locale = strings.ToLower(locale)
+	msgMu.Lock()
+	defer msgMu.Unlock()
+	var m, ok = messages[locale]
+	if !ok {
+		m = make(map[string]string)
+		messages[locale] = m
+	}
+	m[name] = template
+}
+
+// Message 返回指定语言的错误信息模板
+//  依次查找locale,locale的主语言(例如zh-CN的zh)和DefaultLocale
+func Message(locale string, name string) (string, bool) {
+	msgMu.RLock()
+	defer msgMu.RUnlock()
+	locale = strings.ToLower(locale)
+	var candidates = []string{locale}
+	var pos = strings.IndexAny(locale, "-_")
+	if pos > 0 {
+		candidates = append(candidates, locale[:pos])
+	}
+	candidates = append(candidates, strings.ToLower(DefaultLocale))
+	for _, l := range candidates {
+		var t, ok = messages[l][name]
+		if ok {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// ExpandMessage 使用函数节点的参数替换模板中的{0},{1}...和{params},使用value替换{value}
+//  node为nil时只替换{value}
+func ExpandMessage(template string, node *ExecutableFuncNode, value string) string {
+	var pairs = []string{"{value}", value}
+	if node != nil {
+		var params = make([]string, 0)
+		for i, p := range node.Params() {
+			var s = fmt.Sprint(p)
+			pairs = append(pairs, "{"+strconv.Itoa(i)+"}", s)
+			params = append(params, s)
+		}
+		pairs = append(pairs, "{params}", strings.Join(params, ", "))
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// registerMessages 注册基础验证函数的错误信息模板
+func registerMessages() {
+	var zh = map[string]string{
+		"required": "{field}不能为空",
+		"regexp":   "{field}的格式不正确",
+		"<":        "{field}必须小于{0}",
+		"<=":       "{field}必须小于或等于{0}",
+		">":        "{field}必须大于{0}",
+		">=":       "{field}必须大于或等于{0}",
+		"==":       "{field}必须等于{0}",
+		"!=":       "{field}不能等于{0}",
+		"len<":     "{field}的长度必须小于{0}",
+		"len<=":    "{field}的长度必须小于或等于{0}",
+		"len>":     "{field}的长度必须大于{0}",
+		"len>=":    "{field}的长度必须大于或等于{0}",
+		"len==":    "{field}的长度必须等于{0}",
+		"len!=":    "{field}的长度不能等于{0}",
+		"clen<":    "{field}的字符数必须小于{0}",
+		"clen<=":   "{field}的字符数必须小于或等于{0}",
+		"clen>":    "{field}的字符数必须大于{0}",
+		"clen>=":   "{field}的字符数必须大于或等于{0}",
+		"clen==":   "{field}的字符数必须等于{0}",
+		"clen!=":   "{field}的字符数不能等于{0}",
+		"email":    "{field}必须是有效的电子邮件地址",
+		"url":      "{field}必须是有效的url",
+		"ip":       "{field}必须是有效的ip地址",
+		"ipv4":     "{field}必须是有效的ipv4地址",
+		"ipv6":     "{field}必须是有效的ipv6地址",
+		"cidr":     "{field}必须是有效的CIDR网段",
+		"uuid":     "{field}必须是有效的uuid",
+		"hex":      "{field}必须是十六进制字符串",
+		"base64":   "{field}必须是base64编码的字符串",
+		"alpha":    "{field}只能包含英文字母",
+		"alnum":    "{field}只能包含英文字母和数字",
+		"numeric":  "{field}必须是数值",
+		"date":     "{field}必须是{0}格式的日期",
+		"json":     "{field}必须是有效的json",
+		"in":       "{field}必须是{params}中的一个",
+		"notin":    "{field}不能是{params}中的任何一个",
+		"prefix":   "{field}必须以{0}开头",
+		"suffix":   "{field}必须以{0}结尾",
+		"contains": "{field}必须包含{0}",
+	}
+	var en = map[string]string{
+		"required": "{field} is required",
+		"regexp":   "{field} has an invalid format",
+		"<":        "{field} must be less than {0}",
+		"<=":       "{field} must be less than or equal to {0}",
+		">":        "{field} must be greater than {0}",
+		">=":       "{field} must be greater than or equal to {0}",
+		"==":       "{field} must be equal to {0}",
+		"!=":       "{field} must not be equal to {0}",
+		"len<":     "{field} must be shorter than {0} bytes",
+		"len<=":    "{field} must be at most {0} bytes",
+		"len>":     "{field} must be longer than {0} bytes",
+		"len>=":    "{field} must be at least {0} bytes",
+		"len==":    "{field} must be exactly {0} bytes",
+		"len!=":    "{field} must not be {0} bytes",
+		"clen<":    "{field} must be shorter than {0} characters",
+		"clen<=":   "{field} must be at most {0} characters",
+		"clen>":    "{field} must be longer than {0} characters",
+		"clen>=":   "{field} must be at least {0} characters",
+		"clen==":   "{field} must be exactly {0} characters",
+		"clen!=":   "{field} must not be {0} characters",
+		"email":    "{field} must be a valid email address",
+		"url":      "{field} must be a valid url",
+		"ip":       "{field} must be a valid ip address",
+		"ipv4":     "{field} must be a valid ipv4 address",
+		"ipv6":     "{field} must be a valid ipv6 address",
+		"cidr":     "{field} must be a valid CIDR block",
+		"uuid":     "{field} must be a valid uuid",
+		"hex":      "{field} must be a hexadecimal string",
+		"base64":   "{field} must be a base64 encoded string",
+		"alpha":    "{field} must contain only letters",
+		"alnum":    "{field} must contain only letters and digits",
+		"numeric":  "{field} must be a number",
+		"date":     "{field} must be a date in the format {0}",
+		"json":     "{field} must be valid json",
+		"in":       "{field} must be one of {params}",
+		"notin":    "{field} must not be any of {params}",
+		"prefix":   "{field} must start with {0}",
+		"suffix":   "{field} must end with {0}",
+		"contains": "{field} must contain {0}",
+	}
+	for name, t := range zh {
+		RegisterMessage("zh", name, t)
+	}
+	for name, t := range en {
+		RegisterMessage("en", name, t)
+	}
+}
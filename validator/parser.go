@@ -12,6 +12,7 @@ function	-> relop param
 			|  id relop param
 			|  id relop lp optparams rp
 			|  id lp optparams rp
+			|  id
 			|  regexp
 extend		-> and expr
 			|  or expr
@@ -269,6 +270,10 @@ func (this *Parser) function() {
 				return
 			}
 			panic(ErrorInvalidNamedRelopFuncParams.Format(t.Pos, this.Lexer.Token(t)).Error())
+		case TokenKindEOF, TokenKindAnd, TokenKindOr, TokenKindRP:
+			//匹配省略括号的无参函数
+			this.addFuncNode(id)
+			return
 		}
 		panic(ErrorInvalidFuncParams.Format(t.Pos, this.Lexer.Token(t)).Error())
 	case TokenKindRegexp:
@@ -9,6 +9,7 @@ import "reflect"
 //    (1)普通函数:IsOK IsOK() BigThan(1234)  Contain('abc')
 //    (2)名称中包含关系运算符:>=10 Len==11 Complex<(12,22)
 //    (3)正则表达式:/[a-z]+?/
+//    (4)可变参数函数:in('a','b','c') notin(1,2)
 type StringValidator struct {
 	Tree SyntaxNode
 }
@@ -41,7 +42,7 @@ func translate(node SyntaxNode) error {
 		var fnode = node.(*FuncNode)
 		if fnode.Kind() == NodeKindFunc {
 			//处理参数信息
-			var name = fnode.name + sep
+			var types = ""
 			params = make([]interface{}, len(fnode.params))
 			for i, p := range fnode.params {
 				var k = reflect.Invalid
@@ -55,17 +56,17 @@ func translate(node SyntaxNode) error {
 				}
 				var e, ok = CheckType(k)
 				if ok {
-					name += e
+					types += e
 					params[i] = p.Value
 				} else {
 					return ErrorIllegalParam.Format(p.Kind).Error()
 				}
 			}
-			var vf, ok = funcs[name]
+			var vf, ok = findFunc(fnode.name, types)
 			if ok {
 				f = vf
 			} else {
-				err = ErrorUnmatchedFunc.Format(name).Error()
+				err = ErrorUnmatchedFunc.Format(fnode.name + sep + types).Error()
 			}
 		} else {
 			f, err = NewRegFunc(fnode.name)
@@ -74,7 +75,13 @@ func translate(node SyntaxNode) error {
 		if err != nil {
 			return err
 		}
-		var newNode = NewExecutableFuncNode(f, params)
+		var newNode = NewExecutableFuncNode(f, params).(*ExecutableFuncNode)
+		if fnode.Kind() == NodeKindFunc {
+			newNode.name = fnode.name
+		} else {
+			newNode.name = regexpFuncName
+			newNode.exp = fnode.name
+		}
 		if node.Parent().Left() == node {
 			node.Parent().SetLeft(newNode)
 		} else {
@@ -97,6 +104,37 @@ func (this *StringValidator) Validate(str string) bool {
 	return this.validate(this.Tree, str)
 }
 
+// Check 验证str,验证通过时返回nil,否则返回导致验证失败的函数节点
+//  逻辑与返回第一个失败的函数节点,逻辑或两侧均失败时返回右侧失败的函数节点
+func (this *StringValidator) Check(str string) *ExecutableFuncNode {
+	return this.check(this.Tree, str)
+}
+
+// check 递归验证并返回导致验证失败的函数节点
+func (this *StringValidator) check(node SyntaxNode, str string) *ExecutableFuncNode {
+	switch node.Kind() {
+	case NodeKindExecutor:
+		var f = node.(*ExecutableFuncNode)
+		if f.Execute(str) {
+			return nil
+		}
+		return f
+	case NodeKindAnd:
+		var result = this.check(node.Left(), str)
+		if result != nil {
+			return result
+		}
+		return this.check(node.Right(), str)
+	case NodeKindOr:
+		var result = this.check(node.Left(), str)
+		if result == nil {
+			return nil
+		}
+		return this.check(node.Right(), str)
+	}
+	panic(ErrorIllegalNode.Format(node.Kind()))
+}
+
 // validate 递归验证
 func (this *StringValidator) validate(node SyntaxNode, str string) bool {
 	if node.Kind() == NodeKindExecutor {
@@ -37,3 +37,108 @@ func TestStringValidator(t *testing.T) {
 		t.Fatal("校验失败")
 	}
 }
+
+func TestStringValidatorCheck(t *testing.T) {
+	var v, err = NewValidator("string", `len>2 && (<10 || ==100) && /^[0-9]+$/`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ev = v.(ExtendedValidator)
+	var cases = map[string]string{
+		"007":  "",
+		"5":    "len>2",
+		"500":  "==100",
+		"1e10": "==100",
+		"+100": "/^[0-9]+$/",
+	}
+	for str, rule := range cases {
+		var node = ev.Check(str)
+		if rule == "" {
+			if node != nil {
+				t.Fatal("校验失败", str, node)
+			}
+			continue
+		}
+		if node == nil || node.String() != rule {
+			t.Fatal("失败的函数节点错误", str, node)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	var v, _ = NewValidator("string", `len<=5`)
+	var node = v.(ExtendedValidator).Check("abcdef")
+	var template, ok = Message("en-US", node.Name())
+	if !ok || ExpandMessage(template, node, "abcdef") != "{field} must be at most 5 bytes" {
+		t.Fatal("错误信息模板错误", template)
+	}
+	RegisterMessage("zh", "test.value", "值{value}不能大于{0}")
+	template, ok = Message("fr", "test.value")
+	if !ok || ExpandMessage(template, node, "abcdef") != "值abcdef不能大于5" {
+		t.Fatal("默认语言的错误信息模板错误", template)
+	}
+}
+
+func TestExtendedFuncs(t *testing.T) {
+	var cases = []struct {
+		src  string
+		pass []string
+		fail []string
+	}{
+		{`email`, []string{"a@b.com", "x.y+z@example.org"}, []string{"", "a", "a@", "Bob <a@b.com>"}},
+		{`url()`, []string{"http://example.com", "https://a.b/c?d=1"}, []string{"", "example.com", "/path", "http://"}},
+		{`ip`, []string{"127.0.0.1", "::1", "2001:db8::1"}, []string{"", "256.0.0.1", "localhost"}},
+		{`ipv4`, []string{"192.168.1.1"}, []string{"::1", "::ffff:192.168.1.1", "1.2.3"}},
+		{`ipv6`, []string{"::1", "fe80::1", "::ffff:192.168.1.1"}, []string{"192.168.1.1", "gg::1"}},
+		{`cidr`, []string{"10.0.0.0/8", "2001:db8::/32"}, []string{"10.0.0.0", "10.0.0.0/33"}},
+		{`uuid`, []string{"123e4567-e89b-12d3-a456-426614174000", "123E4567-E89B-12D3-A456-426614174000"}, []string{"123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-42661417400g"}},
+		{`hex`, []string{"00ff", "DEADbeef"}, []string{"", "0x00", "fg"}},
+		{`base64`, []string{"aGVsbG8=", "YQ=="}, []string{"", "aGVsbG8", "!!!!"}},
+		{`alpha`, []string{"abcXYZ"}, []string{"", "abc1", "中文"}},
+		{`alnum`, []string{"abc123"}, []string{"", "abc_1", "a b"}},
+		{`numeric`, []string{"0", "-12", "+3.14"}, []string{"", "1e5", "3.", ".5", "abc"}},
+		{`date('2006-01-02')`, []string{"2016-02-29"}, []string{"2015-02-29", "2016/02/01", ""}},
+		{`json`, []string{`{"a":1}`, "[1,2]", "null"}, []string{"", "{a:1}", "[1,"}},
+		{`in('a','b', 'c')`, []string{"a", "c"}, []string{"", "d", "A"}},
+		{`in(1,2,3)`, []string{"1", "3"}, []string{"4", "a", "1.0"}},
+		{`notin('root','admin')`, []string{"bob", ""}, []string{"root", "admin"}},
+		{`notin(0)`, []string{"1", "a"}, []string{"0"}},
+		{`prefix('http')`, []string{"http", "https://"}, []string{"ftp://", ""}},
+		{`suffix('.go')`, []string{"main.go"}, []string{"main.c", "go"}},
+		{`contains('@')`, []string{"a@b", "@"}, []string{"ab", ""}},
+		{`len>0 && (ipv4 || ipv6) && notin('0.0.0.0')`, []string{"1.2.3.4", "::1"}, []string{"", "0.0.0.0", "host"}},
+		{`in('none') || (email && suffix('.com'))`, []string{"none", "a@b.com"}, []string{"a@b.org", "None"}},
+	}
+	for _, c := range cases {
+		var v, err = NewValidator("string", c.src)
+		if err != nil {
+			t.Fatal(c.src, err)
+		}
+		for _, s := range c.pass {
+			if !v.Validate(s) {
+				t.Fatal("校验失败", c.src, s)
+			}
+		}
+		for _, s := range c.fail {
+			if v.Validate(s) {
+				t.Fatal("校验通过了无效的值", c.src, s)
+			}
+		}
+	}
+}
+
+func TestVariadicFunc(t *testing.T) {
+	var _, err = NewValidator("string", `in('a',1)`)
+	if err == nil {
+		t.Fatal("参数类型不一致的可变参数函数没有报告错误")
+	}
+	var v, _ = NewValidator("string", `in('a','b')`)
+	var node = v.(ExtendedValidator).Check("c")
+	if node == nil || node.String() != "in('a','b')" {
+		t.Fatal("失败的函数节点错误", node)
+	}
+	var template, _ = Message("en", node.Name())
+	if ExpandMessage(template, node, "c") != "{field} must be one of a, b" {
+		t.Fatal("可变参数函数的错误信息错误", template)
+	}
+}
@@ -1,6 +1,9 @@
 package validator
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // 节点类型
 type NodeKind byte
@@ -185,6 +188,8 @@ type ExecutableFuncNode struct {
 	BaseNode
 	validator ValidatorFunc //验证器函数
 	params    []interface{} //参数列表
+	name      string        //函数名称,正则函数为regexp
+	exp       string        //正则表达式,普通函数为空
 }
 
 // NewExecutableFuncNode 创建可执行执行函数节点
@@ -198,6 +203,8 @@ func NewExecutableFuncNode(v ValidatorFunc, params []interface{}) SyntaxNode {
 		},
 		v,
 		params,
+		"",
+		"",
 	}
 }
 
@@ -205,3 +212,36 @@ func NewExecutableFuncNode(v ValidatorFunc, params []interface{}) SyntaxNode {
 func (this *ExecutableFuncNode) Execute(str string) bool {
 	return this.validator.Validate(str, this.params...)
 }
+
+// Name 返回函数名称,例如len<=,in,正则函数返回regexp
+func (this *ExecutableFuncNode) Name() string {
+	return this.name
+}
+
+// Params 返回函数的参数列表,正则函数返回正则表达式
+func (this *ExecutableFuncNode) Params() []interface{} {
+	if this.exp != "" {
+		return []interface{}{this.exp}
+	}
+	return this.params
+}
+
+// String 返回函数的字符串形式,例如len>2,in('a','b'),/[a-z]+/
+func (this *ExecutableFuncNode) String() string {
+	if this.name == regexpFuncName {
+		return "/" + this.exp + "/"
+	}
+	var params = make([]string, len(this.params))
+	for i, p := range this.params {
+		var s, ok = p.(string)
+		if ok {
+			params[i] = "'" + s + "'"
+		} else {
+			params[i] = fmt.Sprint(p)
+		}
+	}
+	if len(params) == 1 && relopNameReg.MatchString(this.name) {
+		return this.name + params[0]
+	}
+	return this.name + "(" + strings.Join(params, ",") + ")"
+}
@@ -8,6 +8,13 @@ type Validator interface {
 	Validate(str string) bool
 }
 
+// 扩展验证器,验证失败时能够返回导致失败的函数节点
+type ExtendedValidator interface {
+	Validator
+	// Check 验证str,验证通过时返回nil,否则返回导致验证失败的函数节点
+	Check(str string) *ExecutableFuncNode
+}
+
 // 创建器
 //  suorce: 用于创建验证器的信息
 type ValidatorCreator func(source string) (Validator, error)
@@ -0,0 +1,344 @@
+package web
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kdada/tinygo/meta"
+)
+
+// 请求体错误,请求体超过最大长度时状态码为413,无法解析时为400
+type BodyError struct {
+	Status  StatusCode //Http状态码
+	Message string     //错误信息
+}
+
+// Error 返回错误信息
+func (this *BodyError) Error() string {
+	return this.Message
+}
+
+// BodyFormat 返回请求体的格式,application/json(包括+json)返回json,application/xml和text/xml(包括+xml)返回xml,其他返回空字符串
+func (this *Context) BodyFormat() string {
+	var mediaType, _, err = mime.ParseMediaType(this.HttpContext.Request.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return "json"
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return "xml"
+	}
+	return ""
+}
+
+// Body 读取请求体,请求体只读取一次,超过HttpConfig.MaxBodyBytes时返回错误
+func (this *Context) Body() ([]byte, error) {
+	if this.body == nil {
+		var r = this.HttpContext.Request
+		var max = int64(this.Processor.Config.MaxBodyBytes)
+		var body = make([]byte, 0)
+		var err error
+		if r.Body != nil {
+			body, err = io.ReadAll(io.LimitReader(r.Body, max+1))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if int64(len(body)) > max {
+			return nil, &BodyError{StatusCodeRequestEntityTooLarge, ErrorBodyTooLarge.Format(max).String()}
+		}
+		this.body = body
+	}
+	return this.body, nil
+}
+
+// BindBody 根据Content-Type将json或xml请求体解码到v中,v必须是指针
+func (this *Context) BindBody(v interface{}) error {
+	var body, err = this.Body()
+	if err != nil {
+		return err
+	}
+	var format = this.BodyFormat()
+	switch format {
+	case "json":
+		err = json.Unmarshal(body, v)
+	case "xml":
+		err = xml.Unmarshal(body, v)
+	default:
+		return &BodyError{StatusCodeBadRequest, ErrorUnsupportedBody.Format(this.HttpContext.Request.Header.Get("Content-Type")).String()}
+	}
+	if err != nil {
+		return &BodyError{StatusCodeBadRequest, ErrorInvalidBody.Format(format, err.Error()).String()}
+	}
+	return nil
+}
+
+// StructContainer 请求体为json或xml时,返回使用请求体解码结构体s后的值容器,其他情况和嵌套字段的值容器返回nil
+func (this *ContextValueContainer) StructContainer(s reflect.Type) (meta.ValueContainer, error) {
+	var format = this.Context.BodyFormat()
+	if format == "" || this.Prefix != "" {
+		return nil, nil
+	}
+	var body, err = this.Context.Body()
+	if err != nil {
+		return nil, err
+	}
+	if len(strings.TrimSpace(string(body))) <= 0 {
+		return nil, nil
+	}
+	var value = reflect.New(s)
+	err = this.Context.BindBody(value.Interface())
+	if err != nil {
+		return nil, err
+	}
+	if format == "json" {
+		return newBodyValueContainer(this, value.Elem(), body, true), nil
+	}
+	return newBodyValueContainer(this, value.Elem(), nil, false), nil
+}
+
+// 请求体值容器,使用请求体解码的结构体提供字段值
+// 优先级:web.Processor.Finders > 路由参数 > 请求体 > 其他请求参数
+//  包含结构体的字段(结构体,结构体指针以及元素包含结构体的切片和map)使用子值容器逐个生成子字段,子字段同样使用vld标签校验
+//  json请求体根据对象中是否存在key判断字段是否存在,xml请求体无法区分零值和不存在的元素,零值字段视为不存在并使用其他请求参数
+type BodyValueContainer struct {
+	Container *ContextValueContainer     //请求体中不存在的字段使用的值容器
+	Value     reflect.Value              //请求体解码后的值,顶层为结构体,嵌套字段的值容器可以是结构体,切片或map
+	isJson    bool                       //是否为json请求体
+	keys      map[string]json.RawMessage //Value为json对象时对象中的key和值
+	items     []json.RawMessage          //Value为json数组时数组中的值
+}
+
+// newBodyValueContainer 创建请求体值容器
+//  raw:value对应的json,json为false时不使用
+func newBodyValueContainer(container *ContextValueContainer, value reflect.Value, raw json.RawMessage, isJson bool) *BodyValueContainer {
+	var bvc = &BodyValueContainer{Container: container, Value: value, isJson: isJson}
+	if isJson {
+		if json.Unmarshal(raw, &bvc.keys) != nil {
+			json.Unmarshal(raw, &bvc.items)
+		}
+	}
+	return bvc
+}
+
+// Contains 检查请求体中是否存在指定名称的字段,不存在时使用Container查找
+//  包含结构体的字段存在于请求体中时返回false,由meta.GenerateNested使用Nested和Keys生成
+func (this *BodyValueContainer) Contains(name string, t reflect.Type) (meta.ValueProvider, bool) {
+	var context = this.Container.Context
+	var _, special = context.Processor.Finders[t.String()]
+	var _, param = context.Param(this.Container.path(name))
+	if !special && !param {
+		var v, _, ok = this.child(name)
+		if ok && nestedType(t) {
+			return nil, false
+		}
+		if ok && v.Type() == t {
+			return &BodyValueProvider{v}, true
+		}
+	}
+	return this.Container.Contains(name, t)
+}
+
+// Nested 返回名称为name的嵌套字段的值容器,请求体中不存在该字段时使用其他请求参数
+func (this *BodyValueContainer) Nested(name string) meta.NestedValueContainer {
+	var sub = this.Container.Nested(name).(*ContextValueContainer)
+	var v, raw, ok = this.child(name)
+	if !ok {
+		return sub
+	}
+	return newBodyValueContainer(sub, v, raw, this.isJson)
+}
+
+// Keys 返回名称为name的嵌套字段在请求体中的子字段名称,切片索引或map的key,请求体中不存在该字段时使用其他请求参数
+func (this *BodyValueContainer) Keys(name string) []string {
+	var v, raw, ok = this.child(name)
+	if !ok {
+		return this.Container.Keys(name)
+	}
+	var sub = newBodyValueContainer(this.Container, v, raw, this.isJson)
+	v = indirect(v)
+	var result = make([]string, 0)
+	switch {
+	case !v.IsValid():
+	case v.Kind() == reflect.Struct:
+		for _, field := range reflect.VisibleFields(v.Type()) {
+			if field.Anonymous || !field.IsExported() {
+				continue
+			}
+			if _, _, exist := sub.child(field.Name); exist {
+				result = append(result, field.Name)
+			}
+		}
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			result = append(result, strconv.Itoa(i))
+		}
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		for _, k := range v.MapKeys() {
+			result = append(result, k.String())
+		}
+		sort.Strings(result)
+	}
+	return result
+}
+
+// child 返回当前值中名称为name的字段,切片元素或map元素,以及对应的json
+//  return:(值,json,是否存在于请求体中)
+func (this *BodyValueContainer) child(name string) (reflect.Value, json.RawMessage, bool) {
+	var v = indirect(this.Value)
+	if !v.IsValid() {
+		return reflect.Value{}, nil, false
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		var field, ok = v.Type().FieldByName(name)
+		if !ok || !field.IsExported() {
+			return reflect.Value{}, nil, false
+		}
+		var fv, err = v.FieldByIndexErr(field.Index)
+		if err != nil {
+			return reflect.Value{}, nil, false
+		}
+		if !this.isJson {
+			return fv, nil, !fv.IsZero()
+		}
+		var raw, exist = this.key(jsonName(&field))
+		return fv, raw, exist
+	case reflect.Slice, reflect.Array:
+		var i, err = strconv.Atoi(name)
+		if err != nil || i < 0 || i >= v.Len() {
+			return reflect.Value{}, nil, false
+		}
+		var raw json.RawMessage
+		if i < len(this.items) {
+			raw = this.items[i]
+		}
+		return v.Index(i), raw, true
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, nil, false
+		}
+		var mv = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !mv.IsValid() {
+			return reflect.Value{}, nil, false
+		}
+		return mv, this.keys[name], true
+	}
+	return reflect.Value{}, nil, false
+}
+
+// key 返回json对象中与key对应的值,与encoding/json相同,优先使用大小写相同的key
+func (this *BodyValueContainer) key(key string) (json.RawMessage, bool) {
+	if key == "" {
+		return nil, false
+	}
+	var raw, ok = this.keys[key]
+	if ok {
+		return raw, true
+	}
+	for k, v := range this.keys {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// jsonName 返回字段在json对象中的名称,忽略的字段返回空字符串
+func jsonName(field *reflect.StructField) string {
+	var tag = field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	var name = strings.Split(tag, ",")[0]
+	if name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// indirect 返回指针和接口指向的值,nil指针和nil接口返回无效值
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// 自行解码的类型,不作为嵌套字段处理
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	xmlUnmarshalerType  = reflect.TypeOf((*xml.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// nestedType 判断类型是否需要使用子值容器生成,即结构体,结构体指针以及元素需要使用子值容器生成的切片和key为字符串的map
+//  实现了json.Unmarshaler,xml.Unmarshaler或encoding.TextUnmarshaler的类型(例如time.Time)直接使用请求体解码的值
+func nestedType(t reflect.Type) bool {
+	for _, u := range []reflect.Type{jsonUnmarshalerType, xmlUnmarshalerType, textUnmarshalerType} {
+		if t.Implements(u) || reflect.PointerTo(t).Implements(u) {
+			return false
+		}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Ptr:
+		return t.Elem().Kind() == reflect.Struct && nestedType(t.Elem())
+	case reflect.Slice:
+		return nestedType(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && nestedType(t.Elem())
+	}
+	return false
+}
+
+// 请求体值提供器
+type BodyValueProvider struct {
+	Field reflect.Value //字段值
+}
+
+// String 返回字段的字符串值用于校验,数组,切片和map返回每个元素的字符串值(map按key排序),nil指针返回空数组
+func (this *BodyValueProvider) String() []string {
+	var v = indirect(this.Field)
+	if !v.IsValid() {
+		return []string{}
+	}
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+		var result = make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			result[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return result
+	}
+	if v.Kind() == reflect.Map {
+		var keys = v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		var result = make([]string, len(keys))
+		for i, k := range keys {
+			result[i] = fmt.Sprint(v.MapIndex(k).Interface())
+		}
+		return result
+	}
+	return []string{fmt.Sprint(v.Interface())}
+}
+
+// Value 返回字段值
+func (this *BodyValueProvider) Value() interface{} {
+	return this.Field.Interface()
+}
@@ -0,0 +1,178 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kdada/tinygo/meta"
+)
+
+type bodyAddress struct {
+	City string `vld:"!;len>1" json:"city" xml:"City"`
+}
+
+type bodyItem struct {
+	Sku string `vld:"!;len>0"`
+	Qty int    `vld:"?;>0"`
+}
+
+// 自行解码的结构体,作为普通字段使用请求体解码的值
+type bodyLevel struct {
+	Value int
+}
+
+// UnmarshalText 使用文本长度作为值
+func (this *bodyLevel) UnmarshalText(text []byte) error {
+	this.Value = len(text)
+	return nil
+}
+
+type bodyOrder struct {
+	Context *Context `json:"-" xml:"-"`
+	Name    string   `vld:"!;len>1"`
+	Address *bodyAddress
+	Items   []bodyItem
+	Named   map[string]bodyItem
+	Tags    []string
+	Level   bodyLevel
+	Errors  meta.ValidationErrors `json:"-" xml:"-"`
+}
+
+// 请求体绑定结果
+type bodyResult struct {
+	Order  *bodyOrder
+	Fields []string
+}
+
+// newBodyProcessor 创建/order使用请求体绑定bodyOrder的处理器,result记录绑定结果
+func newBodyProcessor(t *testing.T, result *bodyResult) *HttpProcessor {
+	var root = NewRootRouter()
+	root.AddChild(NewFuncRouter("order", func(param *bodyOrder) PostResult {
+		result.Order = param
+		result.Fields = make([]string, 0)
+		for _, e := range param.Errors {
+			result.Fields = append(result.Fields, e.Field)
+		}
+		sort.Strings(result.Fields)
+		return param.Context.Data([]byte("ok"))
+	}))
+	return newTestProcessor(t, root)
+}
+
+// postBody 使用contentType提交请求体
+func postBody(processor *HttpProcessor, path string, contentType string, body string) *httptest.ResponseRecorder {
+	var req = httptest.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	var w = httptest.NewRecorder()
+	testHandler(processor).ServeHTTP(w, req)
+	return w
+}
+
+func TestBodyNestedValidation(t *testing.T) {
+	var result bodyResult
+	var processor = newBodyProcessor(t, &result)
+	var w = postBody(processor, "/order", "application/json", `{
+		"name": "order",
+		"Address": {"city": "x"},
+		"Items": [{"Sku": "a", "Qty": 2}, {"Sku": "", "Qty": 1}],
+		"Named": {"main": {"Sku": ""}},
+		"Tags": ["t1", "t2"],
+		"Level": "high"
+	}`)
+	if w.Body.String() != "ok" {
+		t.Fatalf("unexpected response %d %q", w.Code, w.Body.String())
+	}
+	//嵌套结构体的字段使用vld标签校验
+	var expected = []string{"Address.City", "Items.1.Sku", "Named.main.Sku"}
+	if !reflect.DeepEqual(result.Fields, expected) {
+		t.Errorf("expected errors %v, got %v", expected, result.Fields)
+	}
+	var order = result.Order
+	if order.Name != "order" || !reflect.DeepEqual(order.Tags, []string{"t1", "t2"}) {
+		t.Errorf("unexpected values %q %v", order.Name, order.Tags)
+	}
+	if order.Level.Value != 4 {
+		t.Errorf("text unmarshaler should be decoded from the body, got %+v", order.Level)
+	}
+
+	w = postBody(processor, "/order", "application/json", `{
+		"Name": "order",
+		"Address": {"City": "Paris"},
+		"Items": [{"Sku": "a", "Qty": 2}, {"Sku": "b", "Qty": 0}],
+		"Named": {"main": {"Sku": "c", "Qty": 3}}
+	}`)
+	order = result.Order
+	if len(result.Fields) != 0 {
+		t.Fatalf("unexpected errors %v", result.Fields)
+	}
+	if order.Address == nil || order.Address.City != "Paris" {
+		t.Errorf("unexpected address %+v", order.Address)
+	}
+	//可选字段未通过校验时不注入
+	var items = []bodyItem{{"a", 2}, {"b", 0}}
+	if !reflect.DeepEqual(order.Items, items) || order.Named["main"] != (bodyItem{"c", 3}) {
+		t.Errorf("unexpected items %+v %+v", order.Items, order.Named)
+	}
+}
+
+func TestBodyNestedFormFallback(t *testing.T) {
+	var result bodyResult
+	var processor = newBodyProcessor(t, &result)
+	//请求体中不存在的嵌套字段使用嵌套表单值
+	postBody(processor, "/order?Address.City=Rome&Items[0][Sku]=s&Name=form", "application/json", `{"Name": "body"}`)
+	var order = result.Order
+	if order.Name != "body" {
+		t.Errorf("body value should take precedence, got %q", order.Name)
+	}
+	if order.Address == nil || order.Address.City != "Rome" || len(order.Items) != 1 || order.Items[0].Sku != "s" {
+		t.Errorf("nested form values were not bound: %+v %+v", order.Address, order.Items)
+	}
+	if len(result.Fields) != 0 {
+		t.Errorf("unexpected errors %v", result.Fields)
+	}
+}
+
+func TestBodyNestedXml(t *testing.T) {
+	var result bodyResult
+	var processor = newBodyProcessor(t, &result)
+	postBody(processor, "/order?Tags=form", "application/xml", `<order>
+		<Name>xml</Name>
+		<Address><City>Oslo</City></Address>
+		<Items><Sku>a</Sku><Qty>1</Qty></Items>
+		<Items><Sku></Sku><Qty>5</Qty></Items>
+	</order>`)
+	var order = result.Order
+	if order.Name != "xml" || order.Address == nil || order.Address.City != "Oslo" {
+		t.Errorf("unexpected xml values %q %+v", order.Name, order.Address)
+	}
+	if !reflect.DeepEqual(result.Fields, []string{"Items.1.Sku"}) {
+		t.Errorf("unexpected errors %v", result.Fields)
+	}
+	//xml请求体中零值字段视为不存在
+	if !reflect.DeepEqual(order.Tags, []string{"form"}) {
+		t.Errorf("zero value xml field should use form values, got %v", order.Tags)
+	}
+}
+
+func TestBodyValueProviderString(t *testing.T) {
+	var cases = []struct {
+		value    interface{}
+		expected []string
+	}{
+		{map[string]int{"b": 2, "a": 1}, []string{"1", "2"}},
+		{[]int{3, 4}, []string{"3", "4"}},
+		{[]byte("ab"), []string{"[97 98]"}},
+		{(*bodyAddress)(nil), []string{}},
+		{json.Number("5"), []string{"5"}},
+	}
+	for _, c := range cases {
+		var vp = &BodyValueProvider{reflect.ValueOf(c.value)}
+		if s := vp.String(); !reflect.DeepEqual(s, c.expected) {
+			t.Errorf("%#v: expected %v, got %v", c.value, c.expected, s)
+		}
+	}
+}
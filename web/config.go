@@ -15,10 +15,32 @@ type HttpConfig struct {
 	Config              config.Config            //配置信息
 	Root                string                   //应用根目录
 	App                 string                   //应用名称
+	Mode                string                   //运行模式,可以为dev或prod,dev模式下的错误页面包含错误详情和调用堆栈,默认为prod
 	Https               bool                     //是否启用https,可选,默认为false
 	Port                int                      //监听端口,可选,默认为80，https为true则默认为443
-	Cert                string                   //证书(PEM)路径,如果启用了https则必填
-	PrivateKey          string                   //私钥(PEM)路径,如果启用了https则必填
+	HttpPort            int                      //启用https时同时监听的http端口,默认为0(不监听)
+	HttpsRedirect       bool                     //是否将HttpPort上的请求重定向到https,默认为false(与https提供相同的内容)
+	HSTSMaxAge          int                      //https响应中Strict-Transport-Security的max-age,单位为秒,默认为0(不发送)
+	HSTSSubDomains      bool                     //Strict-Transport-Security是否包含includeSubDomains,默认为false
+	ProxyProtocol       bool                     //连接是否以PROXY协议(v1或v2)头开始,默认为false
+	TrustedProxies      string                   //受信任的代理,以逗号分隔的ip或者CIDR,来自这些地址的连接才会使用Forwarded和X-Forwarded-*头,默认为空
+	Listen              string                   //监听方式,可以为tcp,unix或fd,默认为tcp
+	UnixSocket          string                   //Unix域套接字路径,Listen为unix时必填
+	UnixSocketMode      string                   //Unix域套接字文件权限(八进制),默认为0660
+	ListenFd            string                   //继承的监听器,Listen为fd时使用,可以为文件描述符或者systemd[:序号或名称],默认为systemd
+	Cert                string                   //证书(PEM)路径,如果启用了https并且没有设置SNI则必填
+	PrivateKey          string                   //私钥(PEM)路径,如果启用了https并且没有设置SNI则必填
+	SNI                 string                   //根据主机名选择的证书,格式如: example.com|a.crt|a.key,*.example.org|b.crt|b.key
+	ClientCA            string                   //客户端CA证书(PEM)路径,设置后验证客户端证书
+	ClientAuth          string                   //客户端证书验证方式,可以为none,request,optional,require,设置了ClientCA时默认为require
+	TLSMinVersion       string                   //最低tls版本,可以为1.0,1.1,1.2,1.3,默认为1.2
+	TLSCipherSuites     string                   //允许的加密套件名称,以逗号分隔,默认为空(使用go的默认设置)
+	DrainTimeout        int                      //停止时等待请求完成的最长时间,单位为秒,默认为30
+	ReadTimeout         int                      //读取整个请求的超时时间,单位为秒,默认为30,0表示不限制
+	ReadHeaderTimeout   int                      //读取请求头的超时时间,单位为秒,默认为10,0表示不限制
+	WriteTimeout        int                      //写入响应的超时时间,单位为秒,默认为0(不限制)
+	IdleTimeout         int                      //keep-alive连接的空闲超时时间,单位为秒,默认为120,0表示不限制
+	MaxHeaderBytes      int                      //请求头的最大字节数,默认为1 MB
 	Home                string                   //首页地址
 	Session             bool                     //是否启用session
 	SessionType         string                   //session类型,参考tinygo/session,默认为memory
@@ -48,6 +70,7 @@ type HttpConfig struct {
 	TemplateExt         string                   //视图文件扩展名
 	TemplateName        string                   //模板文件内部分模板名,用于返回部分视图时使用
 	MaxRequestMemory    int                      //单次请求最大占用内存大小,默认32 MB
+	MaxBodyBytes        int                      //json和xml请求体的最大字节数,默认为1 MB
 	TemplateConfig      *template.TemplateConfig //视图模板配置
 }
 
@@ -56,10 +79,32 @@ func NewHttpConfig() *HttpConfig {
 	// Http配置
 	return &HttpConfig{
 		App:                 "app",
+		Mode:                "prod",
 		Https:               false,
 		Port:                80,
+		HttpPort:            0,
+		HttpsRedirect:       false,
+		HSTSMaxAge:          0,
+		HSTSSubDomains:      false,
+		ProxyProtocol:       false,
+		TrustedProxies:      "",
+		Listen:              "tcp",
+		UnixSocket:          "",
+		UnixSocketMode:      "0660",
+		ListenFd:            "systemd",
 		Cert:                "",
 		PrivateKey:          "",
+		SNI:                 "",
+		ClientCA:            "",
+		ClientAuth:          "",
+		TLSMinVersion:       "1.2",
+		TLSCipherSuites:     "",
+		DrainTimeout:        30,
+		ReadTimeout:         30,
+		ReadHeaderTimeout:   10,
+		WriteTimeout:        0,
+		IdleTimeout:         120,
+		MaxHeaderBytes:      1 << 20,
 		Home:                "",
 		Session:             true,
 		SessionType:         "memory",
@@ -89,6 +134,7 @@ func NewHttpConfig() *HttpConfig {
 		TemplateExt:         "html",
 		TemplateName:        "Content",
 		MaxRequestMemory:    32 << 20,
+		MaxBodyBytes:        1 << 20,
 		TemplateConfig:      template.NewTemplateConfig(),
 	}
 }
@@ -115,6 +161,10 @@ func ReadHttpConfig(appDir string, configPath string) (*HttpConfig, error) {
 	if err == nil {
 		httpCfg.App = strValue
 	}
+	strValue, err = global.String("Mode")
+	if err == nil {
+		httpCfg.Mode = strValue
+	}
 	boolValue, err = global.Bool("Https")
 	if err == nil {
 		httpCfg.Https = boolValue
@@ -127,6 +177,46 @@ func ReadHttpConfig(appDir string, configPath string) (*HttpConfig, error) {
 			httpCfg.Port = 443
 		}
 	}
+	intValue, err = global.Int("HttpPort")
+	if err == nil {
+		httpCfg.HttpPort = intValue
+	}
+	boolValue, err = global.Bool("HttpsRedirect")
+	if err == nil {
+		httpCfg.HttpsRedirect = boolValue
+	}
+	intValue, err = global.Int("HSTSMaxAge")
+	if err == nil {
+		httpCfg.HSTSMaxAge = intValue
+	}
+	boolValue, err = global.Bool("HSTSSubDomains")
+	if err == nil {
+		httpCfg.HSTSSubDomains = boolValue
+	}
+	boolValue, err = global.Bool("ProxyProtocol")
+	if err == nil {
+		httpCfg.ProxyProtocol = boolValue
+	}
+	strValue, err = global.String("TrustedProxies")
+	if err == nil {
+		httpCfg.TrustedProxies = strValue
+	}
+	strValue, err = global.String("Listen")
+	if err == nil {
+		httpCfg.Listen = strValue
+	}
+	strValue, err = global.String("UnixSocket")
+	if err == nil {
+		httpCfg.UnixSocket = strValue
+	}
+	strValue, err = global.String("UnixSocketMode")
+	if err == nil {
+		httpCfg.UnixSocketMode = strValue
+	}
+	strValue, err = global.String("ListenFd")
+	if err == nil {
+		httpCfg.ListenFd = strValue
+	}
 	strValue, err = global.String("Cert")
 	if err == nil {
 		httpCfg.Cert = strValue
@@ -135,6 +225,50 @@ func ReadHttpConfig(appDir string, configPath string) (*HttpConfig, error) {
 	if err == nil {
 		httpCfg.PrivateKey = strValue
 	}
+	strValue, err = global.String("SNI")
+	if err == nil {
+		httpCfg.SNI = strValue
+	}
+	strValue, err = global.String("ClientCA")
+	if err == nil {
+		httpCfg.ClientCA = strValue
+	}
+	strValue, err = global.String("ClientAuth")
+	if err == nil {
+		httpCfg.ClientAuth = strValue
+	}
+	strValue, err = global.String("TLSMinVersion")
+	if err == nil {
+		httpCfg.TLSMinVersion = strValue
+	}
+	strValue, err = global.String("TLSCipherSuites")
+	if err == nil {
+		httpCfg.TLSCipherSuites = strValue
+	}
+	intValue, err = global.Int("DrainTimeout")
+	if err == nil {
+		httpCfg.DrainTimeout = intValue
+	}
+	intValue, err = global.Int("ReadTimeout")
+	if err == nil {
+		httpCfg.ReadTimeout = intValue
+	}
+	intValue, err = global.Int("ReadHeaderTimeout")
+	if err == nil {
+		httpCfg.ReadHeaderTimeout = intValue
+	}
+	intValue, err = global.Int("WriteTimeout")
+	if err == nil {
+		httpCfg.WriteTimeout = intValue
+	}
+	intValue, err = global.Int("IdleTimeout")
+	if err == nil {
+		httpCfg.IdleTimeout = intValue
+	}
+	intValue, err = global.Int("MaxHeaderBytes")
+	if err == nil {
+		httpCfg.MaxHeaderBytes = intValue
+	}
 	strValue, err = global.String("Home")
 	if err == nil {
 		httpCfg.Home = strValue
@@ -252,6 +386,10 @@ func ReadHttpConfig(appDir string, configPath string) (*HttpConfig, error) {
 	if err == nil {
 		httpCfg.MaxRequestMemory = intValue
 	}
+	intValue, err = global.Int("MaxBodyBytes")
+	if err == nil {
+		httpCfg.MaxBodyBytes = intValue
+	}
 
 	//读取视图配置
 	if httpCfg.LayoutConfigPath != "" {
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"crypto/x509"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -66,18 +67,19 @@ type Context struct {
 	Session     session.Session        //http会话
 	CSRF        session.Session        //csrf会话
 	End         router.Router          //处理当前上下文的路由
+	Root        router.Router          //处理当前上下文的根路由,由请求的主机确定
+	Group       *RouteGroup            //处理当前上下文的路由所在的路由组,不存在时为nil
 	Processor   *HttpProcessor         //生成当前上下文的处理器
+	forwarded   *forwardedInfo         //客户端信息,第一次使用时解析
+	body        []byte                 //请求体,第一次使用时读取
+	formKeys    map[string][]string    //规范化的表单key到原始key的映射,第一次使用时生成
 }
 
 // NewContext 创建上下文信息
 func NewContext(segments []string, context *connector.HttpContext, processor *HttpProcessor) (*Context, error) {
-	var contentType = context.Request.Header.Get("Content-Type")
-	var err error
-	if strings.HasPrefix(contentType, "multipart/form-data") {
-		err = context.Request.ParseMultipartForm(int64(processor.Config.MaxRequestMemory))
-	} else {
-		err = context.Request.ParseForm()
-	}
+	//multipart表单在路由匹配之后解析(ParseMultipartForm),以便使用路由组的MaxRequestMemory
+	//路由匹配之前(例如Request事件中)Request.Form不包含multipart表单的值
+	var err = context.Request.ParseForm()
 	if err != nil {
 		return nil, err
 	}
@@ -103,19 +105,32 @@ func (this *Context) Cookie(name string) (*http.Cookie, bool) {
 	return c, true
 }
 
+// PeerCertificate 获取已通过验证的客户端证书,未使用tls或客户端证书未经过验证时返回false
+func (this *Context) PeerCertificate() (*x509.Certificate, bool) {
+	var state = this.HttpContext.Request.TLS
+	if state == nil || len(state.VerifiedChains) <= 0 || len(state.VerifiedChains[0]) <= 0 {
+		return nil, false
+	}
+	return state.VerifiedChains[0][0], true
+}
+
 // Value 返回值
 func (this *Context) Value(name string) (string, bool) {
 	return this.HttpContext.Request.Form.Get(name), true
 }
 
-// Values 返回值数组
+// Values 返回值数组,name不存在时查找等价的表单key,例如Tags对应Tags[],Address.City对应Address[City]
 func (this *Context) Values(name string) ([]string, bool) {
 	var result, ok = this.HttpContext.Request.Form[name]
+	if !ok {
+		result, ok = this.formValues(name)
+	}
 	return result, ok
 }
 
 // SetValue 设置值
 func (this *Context) SetValue(name string, value string) {
+	this.formKeys = nil
 	var _, ok = this.HttpContext.Request.Form[name]
 	if ok {
 		this.HttpContext.Request.Form.Add(name, value)
@@ -184,7 +199,7 @@ func (this *Context) ParamFile(key string) (*FormFile, error) {
 func (this *Context) ParamFiles(key string) ([]*FormFile, error) {
 	var r = this.HttpContext.Request
 	if r.MultipartForm == nil {
-		err := r.ParseMultipartForm(int64(this.Processor.Config.MaxRequestMemory))
+		err := r.ParseMultipartForm(int64(this.MaxRequestMemory()))
 		if err != nil {
 			return nil, err
 		}
@@ -235,6 +250,26 @@ func (this *Context) Json(data interface{}) *JsonResult {
 	return result
 }
 
+// WebSocket 返回WebSocket结果,写入结果时完成握手并使用handler处理连接
+func (this *Context) WebSocket(handler WebSocketHandler) *WebSocketResult {
+	var result = new(WebSocketResult)
+	result.Status = 101
+	result.Context = this
+	result.Handler = handler
+	return result
+}
+
+// EventStream 返回事件流(Server-Sent Events)结果,写入结果时使用handler发送事件
+func (this *Context) EventStream(handler SSEHandler) *SSEResult {
+	var result = new(SSEResult)
+	result.Status = 200
+	result.ContentType = "text/event-stream; charset=utf-8"
+	result.Context = this
+	result.Handler = handler
+	result.Heartbeat = DefaultSSEHeartbeat
+	return result
+}
+
 // Xml 返回Xml类型结果
 func (this *Context) Xml(data interface{}) *XmlResult {
 	var result = new(XmlResult)
@@ -244,9 +279,40 @@ func (this *Context) Xml(data interface{}) *XmlResult {
 	return result
 }
 
+// ApiFormat 返回Api()使用的格式,优先使用路由组的配置
+func (this *Context) ApiFormat() string {
+	if this.Group != nil {
+		var api = this.Group.Api()
+		if api != "" {
+			return api
+		}
+	}
+	return this.Processor.Config.Api
+}
+
+// MaxRequestMemory 返回解析multipart表单时占用的最大内存,优先使用路由组的配置
+func (this *Context) MaxRequestMemory() int {
+	if this.Group != nil {
+		var size = this.Group.MaxRequestMemory()
+		if size > 0 {
+			return size
+		}
+	}
+	return this.Processor.Config.MaxRequestMemory
+}
+
+// ParseMultipartForm 解析multipart表单,不是multipart表单或已经解析时不做任何处理,路由匹配之前调用时使用处理器的配置
+func (this *Context) ParseMultipartForm() error {
+	var r = this.HttpContext.Request
+	if r.MultipartForm != nil || !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return nil
+	}
+	return r.ParseMultipartForm(int64(this.MaxRequestMemory()))
+}
+
 // Api 返回Api类型结果
 func (this *Context) Api(data interface{}) HttpResult {
-	switch this.Processor.Config.Api {
+	switch this.ApiFormat() {
 	case "json":
 		{
 			return this.Json(data)
@@ -271,6 +337,32 @@ func (this *Context) Api(data interface{}) HttpResult {
 	return this.Json(data)
 }
 
+// ApiStatus 返回指定状态码的Api类型结果
+func (this *Context) ApiStatus(status StatusCode, data interface{}) HttpResult {
+	var result = this.Api(data)
+	switch r := result.(type) {
+	case *JsonResult:
+		r.Status = status
+	case *XmlResult:
+		r.Status = status
+	}
+	return result
+}
+
+// ErrorPage 返回错误页面结果,Accept包含text/html时返回Html页面,否则使用与Api()相同的方式返回json或xml
+//  dev模式下错误页面包含err的详细信息
+func (this *Context) ErrorPage(status StatusCode, err error) HttpResult {
+	var page = NewErrorPage(this.Processor.Config, status, err)
+	if strings.Contains(this.HttpContext.Request.Header.Get("Accept"), "text/html") {
+		var result = new(ErrorPageResult)
+		result.Status = status
+		result.ContentType = "text/html; charset=utf-8"
+		result.Page = page
+		return result
+	}
+	return this.ApiStatus(status, page)
+}
+
 // NotFound 返回NotFound类型结果
 func (this *Context) NotFound() *NotFoundResult {
 	var result = new(NotFoundResult)
@@ -279,6 +371,35 @@ func (this *Context) NotFound() *NotFoundResult {
 	return result
 }
 
+// MethodNotAllowed 返回405结果
+//  allow:允许的Http方法,写入Allow头
+func (this *Context) MethodNotAllowed(allow []string) *MethodNotAllowedResult {
+	var result = new(MethodNotAllowedResult)
+	result.Status = StatusCodeMethodNotAllowed
+	result.Allow = allow
+	return result
+}
+
+// Options 返回Options请求的结果
+//  allow:允许的Http方法,写入Allow头
+func (this *Context) Options(allow []string) *AllowedMethodsResult {
+	var result = new(AllowedMethodsResult)
+	result.Status = StatusCodeNoContent
+	result.Allow = allow
+	return result
+}
+
+// URLFor 根据路由别名生成url,优先查找当前上下文的根路由,参考HttpProcessor.URLFor
+func (this *Context) URLFor(alias string, params map[string]string) (string, error) {
+	if this.Root != nil {
+		var r, err = router.FindAlias(this.Root, alias)
+		if err == nil {
+			return router.BuildURL(r, params)
+		}
+	}
+	return this.Processor.URLFor(alias, params)
+}
+
 // Redirect 返回临时重定向类型结果
 func (this *Context) Redirect(url string) *RedirectResult {
 	var result = new(RedirectResult)
@@ -30,12 +30,27 @@ const (
 	ErrorFirstReturnMustBeResult Error = "ErrorFirstReturnMustBeResult(W10040):第一个返回值类型(%s)不符合web.Result接口"
 	ErrorNoReturn                Error = "ErrorNoReturn(W10041):函数(%s)至少拥有一个返回值并且第一个返回值必须符合web.Result类型"
 
-	ErrorParamNotExist  Error = "ErrorParamNotExist(W10100):参数(%s)不存在"
-	ErrorRouterNotFound Error = "ErrorRouterNotFound(W10110):路由(%s)不存在"
-	ErrorInvalidContext Error = "ErrorInvalidContext(W10120):无效的上下文(%s),无法转换为web.Context"
+	ErrorParamNotExist    Error = "ErrorParamNotExist(W10100):参数(%s)不存在"
+	ErrorRouterNotFound   Error = "ErrorRouterNotFound(W10110):路由(%s)不存在"
+	ErrorMethodNotAllowed Error = "ErrorMethodNotAllowed(W10111):路由(%s)不允许%s方法,允许的方法为%s"
+	ErrorInvalidContext   Error = "ErrorInvalidContext(W10120):无效的上下文(%s),无法转换为web.Context"
+	ErrorPanic            Error = "ErrorPanic(W10130):请求(%s)处理过程中出现panic:%v"
+
+	ErrorBodyTooLarge    Error = "ErrorBodyTooLarge(W10140):请求体超过最大长度(%d)"
+	ErrorInvalidBody     Error = "ErrorInvalidBody(W10141):%s请求体解析失败:%s"
+	ErrorUnsupportedBody Error = "ErrorUnsupportedBody(W10142):不支持的请求体类型(%s)"
 
 	ErrorInvalidWriter      Error = "ErrorInvalidWriter(W10200):无效的http写入器"
 	ErrorInvalidPartialView Error = "ErrorInvalidPartialView(W10300):无效的部分视图(%s),找不到指定名称(%s)的模板"
 	ErrorInvalidKey         Error = "ErrorInvalidKey(W10400):无效的Key(%s)"
 	ErrorParamMustBeFunc    Error = "ErrorParamMustBeFunc(W10500):参数必须是函数"
+
+	ErrorInvalidTrustedProxy Error = "ErrorInvalidTrustedProxy(W10600):无效的受信任代理地址(%s)"
+	ErrorInvalidHostPattern  Error = "ErrorInvalidHostPattern(W10610):无效的主机匹配模式(%s)"
+	ErrorInvalidListen       Error = "ErrorInvalidListen(W10620):无效的监听方式(%s),只能为tcp,unix或fd"
+
+	ErrorWebSocketHandshake      Error = "ErrorWebSocketHandshake(W10700):WebSocket握手失败:%s"
+	ErrorInvalidWebSocketMessage Error = "ErrorInvalidWebSocketMessage(W10710):无效的WebSocket消息类型(%d)"
+	ErrorWebSocketClosed         Error = "ErrorWebSocketClosed(W10720):WebSocket连接已经发送关闭帧"
+	ErrorEventStreamClosed       Error = "ErrorEventStreamClosed(W10800):事件流已经结束"
 )
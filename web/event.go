@@ -1,15 +1,22 @@
 package web
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/kdada/tinygo/meta"
+)
 
 // HttpProcessor事件接口
 type HttpProcessorEvent interface {
 	// Request 每次出现一个新请求的时候触发,返回值决定是否处理该请求
+	//  此时还没有进行路由匹配,multipart表单尚未解析,需要时可以调用context.ParseMultipartForm()使用处理器的配置解析
 	Request(processor *HttpProcessor, context *Context) bool
 	// RequestFinish 每次请求正确执行完成的时候触发
 	RequestFinish(processor *HttpProcessor, context *Context, result []interface{})
 	// Error 请求过程中出现任何错误时触发,出现错误时context需要检查是否为nil后才能使用
 	Error(processor *HttpProcessor, context *Context, err error)
+	// Panic 请求过程中出现panic时触发,panic的请求行和调用堆栈已经记录到日志中
+	Panic(processor *HttpProcessor, context *Context, err *PanicError)
 }
 
 // 默认事件
@@ -42,13 +49,30 @@ func (this *DefaultHttpProcessorEvent) RequestFinish(processor *HttpProcessor, c
 	}
 }
 
-// Error 出现错误时触发
+// Error 出现错误时触发,请求方法不被允许时返回405,参数无法通过校验时返回422,请求体错误时返回400或413,其他错误返回404
 func (this *DefaultHttpProcessorEvent) Error(processor *HttpProcessor, context *Context, err error) {
 	if context != nil {
-		var err = context.WriteResult(context.NotFound())
+		var result Result = context.NotFound()
+		switch e := err.(type) {
+		case *MethodNotAllowedError:
+			result = context.MethodNotAllowed(e.Allow)
+		case meta.ValidationErrors:
+			result = context.ValidationReport(e)
+		case *BodyError:
+			result = context.ErrorPage(e.Status, e)
+		}
+		var err = context.WriteResult(result)
 		if err != nil {
 			processor.Logger.Error(err)
 		}
 	}
 	processor.Logger.Error(err)
 }
+
+// Panic 出现panic时触发,返回500错误页面,dev模式下错误页面包含调用堆栈
+func (this *DefaultHttpProcessorEvent) Panic(processor *HttpProcessor, context *Context, err *PanicError) {
+	var e = context.WriteResult(context.ErrorPage(StatusCodeInternalError, err))
+	if e != nil && processor.Logger != nil {
+		processor.Logger.Error(e)
+	}
+}
@@ -2,6 +2,7 @@ package web
 
 import (
 	"reflect"
+	"runtime"
 
 	"github.com/kdada/tinygo/meta"
 	"github.com/kdada/tinygo/router"
@@ -23,6 +24,11 @@ func NewSimpleExecutor(f SimpleExecutorFunc) *SimpleExecutor {
 	return se
 }
 
+// Describe 返回执行器类型(func)和执行函数名称
+func (this *SimpleExecutor) Describe() (string, string) {
+	return "func", funcName(reflect.ValueOf(this.f))
+}
+
 // Excute 执行
 func (this *SimpleExecutor) Execute() (interface{}, error) {
 	var context, ok = this.Context.(*Context)
@@ -39,6 +45,7 @@ func (this *SimpleExecutor) Execute() (interface{}, error) {
 type AdvancedExecutor struct {
 	router.BaseRouterExecutor
 	Method *meta.MethodMetadata //执行方法
+	kind   string               //执行器类型,控制器方法为controller,其他为func
 }
 
 // NewAdvancedExecutor 创建高级执行器
@@ -48,6 +55,14 @@ func NewAdvancedExecutor(method *meta.MethodMetadata) *AdvancedExecutor {
 	return ae
 }
 
+// Describe 返回执行器类型(controller或func)和执行方法名称
+func (this *AdvancedExecutor) Describe() (string, string) {
+	if this.kind == "controller" {
+		return this.kind, this.Method.Method.Type().In(0).String() + "." + this.Method.Name
+	}
+	return "func", funcName(*this.Method.Method)
+}
+
 // Excute 执行
 func (this *AdvancedExecutor) Execute() (interface{}, error) {
 	var context, ok = this.Context.(*Context)
@@ -60,3 +75,15 @@ func (this *AdvancedExecutor) Execute() (interface{}, error) {
 	return nil, ErrorInvalidContext.Format(reflect.TypeOf(this.Context).String()).Error()
 
 }
+
+// funcName 返回函数的完整名称
+func funcName(f reflect.Value) string {
+	if f.Kind() != reflect.Func || f.IsNil() {
+		return ""
+	}
+	var fn = runtime.FuncForPC(f.Pointer())
+	if fn == nil {
+		return ""
+	}
+	return fn.Name()
+}
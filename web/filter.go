@@ -0,0 +1,25 @@
+package web
+
+import (
+	"reflect"
+
+	"github.com/kdada/tinygo/router"
+)
+
+// 环绕过滤器方法,不调用next时返回值作为执行结果,可以返回任意web.Result
+//  例如未登录时返回context.Redirect(url),或者返回状态码为401的json结果
+type AroundFilterFunc func(context *Context, next router.AroundNext) (interface{}, error)
+
+// Around 过滤该请求,上下文不是web.Context时直接执行next
+func (this AroundFilterFunc) Around(context router.RouterContext, next router.AroundNext) (interface{}, error) {
+	var c, ok = context.(*Context)
+	if !ok {
+		return next()
+	}
+	return this(c, next)
+}
+
+// String 返回过滤器方法名称,用于路由列表
+func (this AroundFilterFunc) String() string {
+	return funcName(reflect.ValueOf(this))
+}
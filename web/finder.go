@@ -1,6 +1,7 @@
 package web
 
 import (
+	"fmt"
 	"reflect"
 	"strconv"
 	"time"
@@ -14,7 +15,8 @@ func register(processor *HttpProcessor) {
 	processor.RegisterFinder(reflect.TypeOf(([]*FormFile)(nil)), new(FormFilesCVF))
 	processor.RegisterFinder(reflect.TypeOf(time.Now()), new(TimeCVF))
 
-	// 注册多类型查找器
+	// 注册多类型查找器,路由参数优先于请求参数
+	processor.RegisterMutiTypeFinder(new(RouteParamCVF))
 	processor.RegisterMutiTypeFinder(new(MutiTypeCVF))
 }
 
@@ -55,7 +57,7 @@ type FormFileCVF struct {
 func (this *FormFileCVF) Contains(context *Context, name string, t reflect.Type) bool {
 	var form = context.HttpContext.Request.MultipartForm
 	if form == nil {
-		var err = context.HttpContext.Request.ParseMultipartForm(int64(context.Processor.Config.MaxRequestMemory))
+		var err = context.HttpContext.Request.ParseMultipartForm(int64(context.MaxRequestMemory()))
 		if err != nil {
 			return false
 		}
@@ -127,13 +129,91 @@ func (this *TimeCVF) Value(context *Context, name string, t reflect.Type) interf
 	return nil
 }
 
+// 路由参数查找器,将{key:类型}形式的路由参数转换为字段类型
+type RouteParamCVF struct {
+}
+
+// Contains 查找context是否包含指定的路由参数,路由参数存在时不再使用请求参数
+func (this *RouteParamCVF) Contains(context *Context, name string, t reflect.Type) bool {
+	var _, ok = context.Param(name)
+	return ok
+}
+
+// String 查找context中指定的字符串值
+func (this *RouteParamCVF) String(context *Context, name string, t reflect.Type) []string {
+	var param, _ = context.Param(name)
+	return []string{fmt.Sprint(param)}
+}
+
+// Value 生成指定类型的值,路由参数无法转换为t类型时返回nil
+func (this *RouteParamCVF) Value(context *Context, name string, t reflect.Type) interface{} {
+	var param, _ = context.Param(name)
+	var result, ok = convertParam(param, t)
+	if !ok {
+		return nil
+	}
+	return result
+}
+
+// convertParam 将路由参数转换为t类型,数值类型溢出时转换失败
+func convertParam(param interface{}, t reflect.Type) (interface{}, bool) {
+	var v = reflect.ValueOf(param)
+	if !v.IsValid() {
+		return nil, false
+	}
+	if v.Type().AssignableTo(t) {
+		return param, true
+	}
+	if t.Kind() == reflect.String {
+		return reflect.ValueOf(fmt.Sprint(param)).Convert(t).Interface(), true
+	}
+	var result = reflect.New(t).Elem()
+	switch {
+	case isIntKind(v.Kind()) && isIntKind(t.Kind()):
+		if result.OverflowInt(v.Int()) {
+			return nil, false
+		}
+		result.SetInt(v.Int())
+	case isIntKind(v.Kind()) && isUintKind(t.Kind()):
+		if v.Int() < 0 || result.OverflowUint(uint64(v.Int())) {
+			return nil, false
+		}
+		result.SetUint(uint64(v.Int()))
+	case isIntKind(v.Kind()) && (t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64):
+		result.SetFloat(float64(v.Int()))
+	case v.Type().ConvertibleTo(t) && v.Kind() != reflect.String:
+		result = v.Convert(t)
+	default:
+		return nil, false
+	}
+	return result.Interface(), true
+}
+
+// isIntKind 判断是否为有符号整数类型
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// isUintKind 判断是否为无符号整数类型
+func isUintKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
 // 多类型值查找器
 type MutiTypeCVF struct {
 }
 
 // Contains 查找context是否包含指定的值
 func (this *MutiTypeCVF) Contains(context *Context, name string, t reflect.Type) bool {
-	var _, ok = context.Value(name)
+	var _, ok = context.Values(name)
 	if !ok {
 		return false
 	}
@@ -0,0 +1,178 @@
+package web
+
+import (
+	"net"
+	"strings"
+)
+
+// 转发信息
+type forwardedInfo struct {
+	ip    string //客户端ip
+	proto string //客户端使用的协议
+	host  string //客户端请求的主机
+}
+
+// 转发头中的一个节点
+type forwardedElement struct {
+	ip    net.IP //节点地址,无效地址(例如unknown或_hidden)为nil
+	proto string //协议
+	host  string //主机
+}
+
+// ParseTrustedProxies 解析受信任的代理列表
+//  proxies:以逗号分隔的ip或者CIDR,例如: 127.0.0.1,10.0.0.0/8,::1
+func ParseTrustedProxies(proxies string) ([]*net.IPNet, error) {
+	var result = make([]*net.IPNet, 0)
+	for _, p := range strings.Split(proxies, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, "/") {
+			var ip = net.ParseIP(p)
+			if ip == nil {
+				return nil, ErrorInvalidTrustedProxy.Format(p).Error()
+			}
+			var bits = 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			result = append(result, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		var _, network, err = net.ParseCIDR(p)
+		if err != nil {
+			return nil, ErrorInvalidTrustedProxy.Format(p).Error()
+		}
+		result = append(result, network)
+	}
+	return result, nil
+}
+
+// parseForwardedIP 解析转发头中的地址,地址可以带有端口,ipv6地址可以带有方括号
+func parseForwardedIP(value string) net.IP {
+	value = strings.Trim(strings.TrimSpace(value), `"`)
+	var host, _, err = net.SplitHostPort(value)
+	if err == nil {
+		value = host
+	}
+	return net.ParseIP(strings.Trim(value, "[]"))
+}
+
+// parseForwarded 解析Forwarded头(RFC 7239),例如: for=192.0.2.60;proto=http;by=203.0.113.43, for="[2001:db8::1]"
+func parseForwarded(values []string) []forwardedElement {
+	var result = make([]forwardedElement, 0)
+	for _, value := range values {
+		for _, e := range strings.Split(value, ",") {
+			var element = forwardedElement{}
+			for _, pair := range strings.Split(e, ";") {
+				var kv = strings.SplitN(strings.TrimSpace(pair), "=", 2)
+				if len(kv) != 2 {
+					continue
+				}
+				var v = strings.Trim(kv[1], `"`)
+				switch strings.ToLower(kv[0]) {
+				case "for":
+					element.ip = parseForwardedIP(v)
+				case "proto":
+					element.proto = strings.ToLower(v)
+				case "host":
+					element.host = v
+				}
+			}
+			result = append(result, element)
+		}
+	}
+	return result
+}
+
+// parseXForwarded 解析X-Forwarded-For,X-Forwarded-Proto和X-Forwarded-Host头
+//  X-Forwarded-Proto和X-Forwarded-Host由最外层的代理设置,多个值时使用最左边的值
+func parseXForwarded(forwardedFor []string, proto string, host string) []forwardedElement {
+	proto = strings.ToLower(strings.TrimSpace(strings.Split(proto, ",")[0]))
+	host = strings.TrimSpace(strings.Split(host, ",")[0])
+	var result = make([]forwardedElement, 0)
+	for _, value := range forwardedFor {
+		for _, ip := range strings.Split(value, ",") {
+			result = append(result, forwardedElement{parseForwardedIP(ip), proto, host})
+		}
+	}
+	return result
+}
+
+// isTrustedProxy 判断ip是否为受信任的代理
+func (this *HttpProcessor) isTrustedProxy(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range this.TrustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveForwarded 解析客户端信息,只有直接连接的节点是受信任的代理时才使用转发头
+//  从右向左查找第一个不受信任的地址作为客户端地址,所有地址都受信任时使用最左边的有效地址
+func (this *Context) resolveForwarded() *forwardedInfo {
+	if this.forwarded != nil {
+		return this.forwarded
+	}
+	var r = this.HttpContext.Request
+	var info = &forwardedInfo{r.RemoteAddr, "http", r.Host}
+	if r.TLS != nil {
+		info.proto = "https"
+	}
+	var peer = parseForwardedIP(r.RemoteAddr)
+	if peer != nil {
+		info.ip = peer.String()
+	}
+	if this.Processor != nil && this.Processor.isTrustedProxy(peer) {
+		var elements []forwardedElement
+		var header = r.Header
+		if len(header["Forwarded"]) > 0 {
+			elements = parseForwarded(header["Forwarded"])
+		} else {
+			elements = parseXForwarded(header["X-Forwarded-For"], header.Get("X-Forwarded-Proto"), header.Get("X-Forwarded-Host"))
+		}
+		var client = -1
+		for i := len(elements) - 1; i >= 0; i-- {
+			if elements[i].ip == nil {
+				break
+			}
+			client = i
+			if !this.Processor.isTrustedProxy(elements[i].ip) {
+				break
+			}
+		}
+		if client >= 0 {
+			var element = elements[client]
+			info.ip = element.ip.String()
+			if element.proto != "" {
+				info.proto = element.proto
+			}
+			if element.host != "" {
+				info.host = element.host
+			}
+		}
+	}
+	this.forwarded = info
+	return info
+}
+
+// ClientIP 返回客户端ip,连接来自受信任的代理时使用Forwarded或X-Forwarded-For头中的地址
+func (this *Context) ClientIP() string {
+	return this.resolveForwarded().ip
+}
+
+// Scheme 返回客户端使用的协议(http或https),连接来自受信任的代理时使用Forwarded或X-Forwarded-Proto头中的协议
+func (this *Context) Scheme() string {
+	return this.resolveForwarded().proto
+}
+
+// Host 返回客户端请求的主机,连接来自受信任的代理时使用Forwarded或X-Forwarded-Host头中的主机
+func (this *Context) Host() string {
+	return this.resolveForwarded().host
+}
@@ -0,0 +1,186 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kdada/tinygo/router"
+)
+
+// 路由组,组内的路由共享路由前缀,过滤器和配置
+//  路由组作为前置过滤器添加到组路由中,组内的路由使用最近的路由组的配置,未设置的配置使用上级路由组或处理器的配置
+type RouteGroup struct {
+	Root             router.Router //组路由,即路由前缀的最后一级路由
+	Prefix           string        //从根路由到组路由的完整路由前缀
+	parent           *RouteGroup   //上级路由组
+	api              string        //Api格式
+	maxRequestMemory int           //单次请求最大占用内存
+	csrf             int           //CSRF设置,0:未设置,1:启用,-1:禁用
+}
+
+// NewRouteGroup 创建路由组,路由前缀中已经存在的常规路由会被复用,组路由已经是路由组时返回该路由组
+//  parent:上级路由,通常为根路由
+//  prefix:路由前缀,使用/分割,每一级的格式与NewSpaceRouter的名称相同
+func NewRouteGroup(parent router.Router, prefix string) *RouteGroup {
+	var leaf = parent
+	for _, v := range strings.Split(prefix, "/") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		var r, ok = leaf.Child(v)
+		if !ok {
+			r = NewSpaceRouter(v)
+			leaf.AddChild(r)
+		}
+		leaf = r
+	}
+	var group = groupOf(leaf)
+	if group != nil && group.Root == leaf {
+		return group
+	}
+	var chain = make([]router.Router, 0)
+	for r := leaf; r != nil; r = r.Parent() {
+		chain = append([]router.Router{r}, chain...)
+	}
+	group = &RouteGroup{Root: leaf, Prefix: router.Pattern(chain), parent: group}
+	leaf.AddPreFilter(group)
+	return group
+}
+
+// Group 创建子路由组,子路由组继承当前路由组的过滤器和配置
+func (this *RouteGroup) Group(prefix string) *RouteGroup {
+	return NewRouteGroup(this.Root, prefix)
+}
+
+// Parent 返回上级路由组,不存在时返回nil
+func (this *RouteGroup) Parent() *RouteGroup {
+	return this.parent
+}
+
+// SetApi 设置组内Context.Api()使用的格式(json或xml),为空时使用上级路由组或处理器的配置
+func (this *RouteGroup) SetApi(format string) *RouteGroup {
+	this.api = format
+	return this
+}
+
+// SetMaxRequestMemory 设置组内multipart表单解析时占用的最大内存,小于等于0时使用上级路由组或处理器的配置
+func (this *RouteGroup) SetMaxRequestMemory(size int) *RouteGroup {
+	this.maxRequestMemory = size
+	return this
+}
+
+// SetCSRF 设置组内是否使用CSRF
+//  启用时组内的Post,Put,Patch和Delete请求必须通过Context.ValidateCSRF()验证,需要处理器启用CSRF
+//  禁用时组内请求的Context.CSRF为nil
+func (this *RouteGroup) SetCSRF(enable bool) *RouteGroup {
+	if enable {
+		this.csrf = 1
+	} else {
+		this.csrf = -1
+	}
+	return this
+}
+
+// Api 返回组内使用的Api格式,未设置时返回空字符串
+func (this *RouteGroup) Api() string {
+	for g := this; g != nil; g = g.parent {
+		if g.api != "" {
+			return g.api
+		}
+	}
+	return ""
+}
+
+// MaxRequestMemory 返回组内单次请求最大占用内存,未设置时返回0
+func (this *RouteGroup) MaxRequestMemory() int {
+	for g := this; g != nil; g = g.parent {
+		if g.maxRequestMemory > 0 {
+			return g.maxRequestMemory
+		}
+	}
+	return 0
+}
+
+// CSRF 返回组内是否使用CSRF,第二个返回值表示是否设置
+func (this *RouteGroup) CSRF() (bool, bool) {
+	for g := this; g != nil; g = g.parent {
+		if g.csrf != 0 {
+			return g.csrf > 0, true
+		}
+	}
+	return false, false
+}
+
+// AddPreFilter 添加组内路由的前置过滤器
+func (this *RouteGroup) AddPreFilter(filter router.PreFilter) *RouteGroup {
+	this.Root.AddPreFilter(filter)
+	return this
+}
+
+// AddPostFilter 添加组内路由的后置过滤器
+func (this *RouteGroup) AddPostFilter(filter router.PostFilter) *RouteGroup {
+	this.Root.AddPostFilter(filter)
+	return this
+}
+
+// AddAroundFilter 添加组内路由的环绕过滤器
+func (this *RouteGroup) AddAroundFilter(filter router.AroundFilter) *RouteGroup {
+	this.Root.AddAroundFilter(filter)
+	return this
+}
+
+// AddChild 添加组内路由
+func (this *RouteGroup) AddChild(r router.Router) *RouteGroup {
+	this.Root.AddChild(r)
+	return this
+}
+
+// Controller 添加控制器路由,参考NewControllerRouter
+func (this *RouteGroup) Controller(instance interface{}) *RouteGroup {
+	return this.AddChild(NewControllerRouter(instance))
+}
+
+// Func 添加函数路由,参考NewFuncRouter
+func (this *RouteGroup) Func(name string, function interface{}) *RouteGroup {
+	return this.AddChild(NewFuncRouter(name, function))
+}
+
+// String 返回路由组名称,用于路由列表
+func (this *RouteGroup) String() string {
+	return "group(" + this.Prefix + ")"
+}
+
+// Filter 应用路由组的CSRF设置,只有最近的路由组生效
+func (this *RouteGroup) Filter(context router.RouterContext) bool {
+	var c, ok = context.(*Context)
+	if !ok || c.Group != this {
+		return true
+	}
+	var enable, set = this.CSRF()
+	if !set {
+		return true
+	}
+	if !enable {
+		c.CSRF = nil
+		return true
+	}
+	switch c.HttpContext.Request.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return c.ValidateCSRF()
+	}
+	return true
+}
+
+// groupOf 返回r所在的最近的路由组,不存在时返回nil
+func groupOf(r router.Router) *RouteGroup {
+	for ; r != nil; r = r.Parent() {
+		for _, f := range r.PreFilters() {
+			var group, ok = f.(*RouteGroup)
+			if ok {
+				return group
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouteGroupInheritance(t *testing.T) {
+	var root = NewRootRouter()
+	var api = NewRouteGroup(root, "api")
+	if NewRouteGroup(root, "/api/") != api {
+		t.Error("NewRouteGroup should return the existing group")
+	}
+	var v1 = api.Group("v1")
+	if v1.Parent() != api || v1.Prefix != "/api/v1" {
+		t.Errorf("unexpected group %s with parent %v", v1.Prefix, v1.Parent())
+	}
+	if v1.Api() != "" || v1.MaxRequestMemory() != 0 {
+		t.Error("unset group settings should be empty")
+	}
+	if _, set := v1.CSRF(); set {
+		t.Error("unset CSRF should not be reported as set")
+	}
+	api.SetApi("xml").SetMaxRequestMemory(1024).SetCSRF(false)
+	if v1.Api() != "xml" || v1.MaxRequestMemory() != 1024 {
+		t.Errorf("child group should inherit settings, got %s %d", v1.Api(), v1.MaxRequestMemory())
+	}
+	if enable, set := v1.CSRF(); enable || !set {
+		t.Error("child group should inherit disabled CSRF")
+	}
+	v1.SetApi("json").SetMaxRequestMemory(-1).SetCSRF(true)
+	if v1.Api() != "json" || v1.MaxRequestMemory() != 1024 {
+		t.Errorf("child group should override api only, got %s %d", v1.Api(), v1.MaxRequestMemory())
+	}
+	if enable, _ := v1.CSRF(); !enable {
+		t.Error("child group should override CSRF")
+	}
+	if api.Api() != "xml" {
+		t.Error("child settings should not change the parent group")
+	}
+	//路由前缀中已经存在的路由被复用
+	var v1Router, _ = api.Root.Child("v1")
+	if v1.Root != v1Router || len(api.Root.Children()) != 1 {
+		t.Error("existing prefix routers should be reused")
+	}
+}
+
+// groupSettings 返回当前上下文的路由组和配置
+func groupSettings(context *Context) GetResult {
+	var prefix = ""
+	if context.Group != nil {
+		prefix = context.Group.Prefix
+	}
+	var form = context.HttpContext.Request.MultipartForm != nil
+	return context.Data([]byte(fmt.Sprintf("%s %s %d %v", prefix, context.ApiFormat(), context.MaxRequestMemory(), form)))
+}
+
+// 记录Request事件中请求状态的处理器事件
+type testRequestEvent struct {
+	DefaultHttpProcessorEvent
+	parse     bool //是否在Request事件中解析multipart表单
+	multipart bool //Request事件中是否已经解析multipart表单
+}
+
+// Request 记录multipart表单是否已经解析
+func (this *testRequestEvent) Request(processor *HttpProcessor, context *Context) bool {
+	if this.parse {
+		context.ParseMultipartForm()
+	}
+	this.multipart = context.HttpContext.Request.MultipartForm != nil
+	return true
+}
+
+func TestRouteGroupDispatch(t *testing.T) {
+	var root = NewRootRouter()
+	root.AddChild(NewFuncRouter("settings", groupSettings))
+	var api = NewRouteGroup(root, "api").SetApi("xml").SetMaxRequestMemory(2048)
+	api.Func("settings", groupSettings)
+	api.Group("v1").SetApi("json").Func("settings", groupSettings).Func("upload", func(context *Context) PostResult {
+		return groupSettings(context)
+	})
+	var processor = newTestProcessor(t, root, func(config *HttpConfig) {
+		config.Api = "auto"
+		config.MaxRequestMemory = 4096
+	})
+	var event = new(testRequestEvent)
+	processor.Event = event
+	var cases = map[string]string{
+		"/settings":        " auto 4096",
+		"/api/settings":    "/api xml 2048",
+		"/api/v1/settings": "/api/v1 json 2048",
+	}
+	for path, expected := range cases {
+		var w = httptest.NewRecorder()
+		testHandler(processor).ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Body.String() != expected+" false" {
+			t.Errorf("%s: expected %q, got %q", path, expected+" false", w.Body.String())
+		}
+	}
+	//multipart表单在路由匹配之后解析
+	for _, parse := range []bool{false, true} {
+		event.parse = parse
+		var body = new(bytes.Buffer)
+		var writer = multipart.NewWriter(body)
+		writer.WriteField("name", "value")
+		writer.Close()
+		var req = httptest.NewRequest("POST", "/api/v1/upload", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		var w = httptest.NewRecorder()
+		testHandler(processor).ServeHTTP(w, req)
+		if w.Body.String() != "/api/v1 json 2048 true" {
+			t.Errorf("unexpected multipart response %q", w.Body.String())
+		}
+		if event.multipart != parse || req.FormValue("name") != "value" {
+			t.Errorf("parse=%v: multipart parsed in Request event: %v, form value %q", parse, event.multipart, req.FormValue("name"))
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package web
+
+import (
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kdada/tinygo/router"
+)
+
+// 虚拟主机,使用独立的根路由处理指定主机的请求
+type VirtualHost struct {
+	Pattern string              //主机匹配模式
+	Root    router.Router       //根路由
+	exact   bool                //是否为精确匹配的主机
+	host    string              //精确匹配的主机,已转换为小写
+	literal int                 //普通标签的数量,用于确定匹配顺序
+	regexp  *regexp.Regexp      //主机匹配正则表达式
+	keys    []string            //可提取keys
+	params  []*router.ParamType //keys对应的参数类型,没有指定类型的key为nil
+}
+
+// NewVirtualHost 创建虚拟主机
+//  pattern:主机匹配模式,不区分大小写,不包含端口,以.分隔的每个标签可以是:
+//   普通字符串,例如www.example.com
+//   *,匹配任意一个标签,例如*.example.com
+//   {key}或{key:类型},匹配任意一个标签并作为路由值,例如{tenant}.example.com,类型参考router.ParseReg
+//  root:该主机使用的根路由
+func NewVirtualHost(pattern string, root router.Router) (*VirtualHost, error) {
+	var host = &VirtualHost{Pattern: pattern, Root: root, exact: true}
+	var labels = strings.Split(strings.TrimSuffix(pattern, "."), ".")
+	var exps = make([]string, len(labels))
+	for i, label := range labels {
+		switch {
+		case label == "":
+			return nil, ErrorInvalidHostPattern.Format(pattern).Error()
+		case label == "*":
+			host.exact = false
+			exps[i] = `[^.]+`
+		case strings.HasPrefix(label, "{") && strings.HasSuffix(label, "}"):
+			host.exact = false
+			var key = label[1 : len(label)-1]
+			var exp = `[^.]+`
+			var param *router.ParamType
+			var pos = strings.Index(key, ":")
+			if pos >= 0 {
+				var ok bool
+				param, ok = router.FindParamType(key[pos+1:])
+				if !ok || param.Multi {
+					return nil, ErrorInvalidHostPattern.Format(pattern).Error()
+				}
+				key = key[:pos]
+				exp = param.Exp
+			}
+			if key == "" {
+				return nil, ErrorInvalidHostPattern.Format(pattern).Error()
+			}
+			host.keys = append(host.keys, key)
+			host.params = append(host.params, param)
+			exps[i] = "(" + exp + ")"
+		case strings.ContainsAny(label, "{}*"):
+			return nil, ErrorInvalidHostPattern.Format(pattern).Error()
+		default:
+			host.literal++
+			labels[i] = strings.ToLower(label)
+			exps[i] = regexp.QuoteMeta(labels[i])
+		}
+	}
+	if host.exact {
+		host.host = strings.Join(labels, ".")
+	} else {
+		var reg, err = regexp.Compile("^(?i:" + strings.Join(exps, `\.`) + ")$")
+		if err != nil {
+			return nil, ErrorInvalidHostPattern.Format(pattern).Error()
+		}
+		host.regexp = reg
+	}
+	return host, nil
+}
+
+// Match 匹配主机,主机不能包含端口,匹配成功时返回提取的路由值和解析后的参数
+func (this *VirtualHost) Match(host string) (map[string]string, map[string]interface{}, bool) {
+	if this.exact {
+		return nil, nil, host == this.host
+	}
+	var data = this.regexp.FindStringSubmatch(host)
+	if len(data) != len(this.keys)+1 {
+		return nil, nil, false
+	}
+	var values = make(map[string]string, len(this.keys))
+	var params = make(map[string]interface{}, len(this.keys))
+	for i, key := range this.keys {
+		var v = data[i+1]
+		var param interface{} = v
+		if this.params[i] != nil && this.params[i].Parse != nil {
+			var p, err = this.params[i].Parse(v)
+			if err != nil {
+				return nil, nil, false
+			}
+			param = p
+		}
+		values[key] = v
+		params[key] = param
+	}
+	return values, params, true
+}
+
+// normalizeHost 去掉主机中的端口和末尾的.,并转换为小写
+func normalizeHost(host string) string {
+	var h, _, err = net.SplitHostPort(host)
+	if err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(strings.Trim(host, "[]"), "."))
+}
+
+// AddHost 添加虚拟主机,请求的主机(Context.Host())匹配pattern时使用root处理请求,所有虚拟主机都不匹配时使用Root处理请求
+//  匹配顺序:精确匹配的主机 > 普通标签更多的主机 > 先添加的主机
+//  Favicon,Robots,Static和Home只添加到Root中,虚拟主机需要自行添加相应的路由
+//  pattern:主机匹配模式,参考NewVirtualHost
+func (this *HttpProcessor) AddHost(pattern string, root router.Router) error {
+	var host, err = NewVirtualHost(pattern, root)
+	if err != nil {
+		return err
+	}
+	this.Hosts = append(this.Hosts, host)
+	sort.SliceStable(this.Hosts, func(i, j int) bool {
+		var a, b = this.Hosts[i], this.Hosts[j]
+		if a.exact != b.exact {
+			return a.exact
+		}
+		return a.literal > b.literal
+	})
+	return nil
+}
+
+// RootFor 返回处理当前请求的根路由,虚拟主机提取的路由值会设置到context中
+func (this *HttpProcessor) RootFor(context *Context) router.Router {
+	if len(this.Hosts) > 0 {
+		var host = normalizeHost(context.Host())
+		for _, h := range this.Hosts {
+			var values, params, ok = h.Match(host)
+			if ok {
+				for k, v := range values {
+					context.SetValue(k, v)
+					context.SetParam(k, params[k])
+				}
+				return h.Root
+			}
+		}
+	}
+	return this.Root
+}
+
+// rootOf 返回处理context的根路由
+func (this *HttpProcessor) rootOf(context *Context) router.Router {
+	if context.Root != nil {
+		return context.Root
+	}
+	return this.Root
+}
@@ -0,0 +1,113 @@
+package web
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kdada/tinygo/router"
+)
+
+func TestVirtualHostMatch(t *testing.T) {
+	var cases = []struct {
+		pattern string
+		host    string
+		ok      bool
+		values  map[string]string
+	}{
+		{"www.example.com", "www.example.com", true, nil},
+		{"WWW.Example.com.", "www.example.com", true, nil},
+		{"www.example.com", "api.example.com", false, nil},
+		{"*.example.com", "api.example.com", true, nil},
+		{"*.example.com", "API.EXAMPLE.COM", true, nil},
+		{"*.example.com", "example.com", false, nil},
+		{"*.example.com", "a.b.example.com", false, nil},
+		{"{tenant}.example.com", "acme.example.com", true, map[string]string{"tenant": "acme"}},
+		{"{id:int}.example.com", "12.example.com", true, map[string]string{"id": "12"}},
+		{"{id:int}.example.com", "abc.example.com", false, nil},
+		{"{a}.{b}.example.com", "x.y.example.com", true, map[string]string{"a": "x", "b": "y"}},
+	}
+	for _, c := range cases {
+		var host, err = NewVirtualHost(c.pattern, NewRootRouter())
+		if err != nil {
+			t.Fatalf("%s: %s", c.pattern, err)
+		}
+		var values, _, ok = host.Match(normalizeHost(c.host))
+		if ok != c.ok {
+			t.Errorf("%s should match %s: %v", c.pattern, c.host, c.ok)
+			continue
+		}
+		for k, v := range c.values {
+			if values[k] != v {
+				t.Errorf("%s on %s: expected %s=%s, got %v", c.pattern, c.host, k, v, values)
+			}
+		}
+	}
+	var host, _ = NewVirtualHost("{id:int}.example.com", NewRootRouter())
+	var _, params, _ = host.Match("12.example.com")
+	if params["id"] != int64(12) {
+		t.Errorf("expected parsed int param, got %#v", params["id"])
+	}
+	for _, pattern := range []string{"", "a..b", "{}.example.com", "a*.example.com", "{id:path}.example.com", "{id:none}.example.com"} {
+		if _, err := NewVirtualHost(pattern, NewRootRouter()); err == nil {
+			t.Errorf("expected error for pattern %q", pattern)
+		}
+	}
+}
+
+func TestNormalizeHost(t *testing.T) {
+	var cases = map[string]string{
+		"Example.com:8080": "example.com",
+		"example.com.":     "example.com",
+		"[::1]:8080":       "::1",
+		"[::1]":            "::1",
+		"127.0.0.1:80":     "127.0.0.1",
+	}
+	for host, expected := range cases {
+		if h := normalizeHost(host); h != expected {
+			t.Errorf("%s: expected %s, got %s", host, expected, h)
+		}
+	}
+}
+
+// newHostRoot 创建根路由,/who返回name和tenant路由值
+func newHostRoot(name string) router.Router {
+	var root = NewRootRouter()
+	root.AddChild(NewFuncRouter("who", func(context *Context) GetResult {
+		var tenant, _ = context.Value("tenant")
+		return context.Data([]byte(name + ":" + tenant))
+	}))
+	return root
+}
+
+func TestHostRouting(t *testing.T) {
+	var processor = newTestProcessor(t, newHostRoot("default"))
+	for _, h := range []struct{ pattern, name string }{
+		{"*.example.com", "wildcard"},
+		{"{tenant}.shop.example.com", "tenant"},
+		{"www.example.com", "exact"},
+		{"{tenant}.example.com", "shadowed"},
+	} {
+		if err := processor.AddHost(h.pattern, newHostRoot(h.name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var cases = map[string]string{
+		"www.example.com":           "exact:",
+		"WWW.EXAMPLE.COM:8080":      "exact:",
+		"api.example.com":           "wildcard:",
+		"api.example.com:443":       "wildcard:",
+		"acme.shop.example.com":     "tenant:acme",
+		"acme.shop.example.com:800": "tenant:acme",
+		"example.com":               "default:",
+		"localhost:8080":            "default:",
+	}
+	for host, expected := range cases {
+		var req = httptest.NewRequest("GET", "/who", nil)
+		req.Host = host
+		var w = httptest.NewRecorder()
+		testHandler(processor).ServeHTTP(w, req)
+		if w.Body.String() != expected {
+			t.Errorf("%s: expected %q, got %d %q", host, expected, w.Code, w.Body.String())
+		}
+	}
+}
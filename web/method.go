@@ -0,0 +1,86 @@
+package web
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kdada/tinygo/router"
+)
+
+// 请求路径存在但是不允许请求方法时的错误
+type MethodNotAllowedError struct {
+	Path   string   //请求路径
+	Method string   //请求方法
+	Allow  []string //允许的Http方法
+}
+
+// Error 返回错误信息
+func (this *MethodNotAllowedError) Error() string {
+	return ErrorMethodNotAllowed.Format(this.Path, this.Method, strings.Join(this.Allow, ", ")).String()
+}
+
+// AllowedMethods 返回请求路径允许的Http方法,路径不存在或者路径没有Http方法路由时返回nil
+//  允许Get方法时同时允许Head方法,允许任何方法时同时允许Options方法
+func (this *HttpProcessor) AllowedMethods(context *Context) []string {
+	var segs = context.AllSegments()
+	var pathContext = &router.BaseContext{Segs: segs[:len(segs)-1]}
+	var r, ok = this.rootOf(context).Find(pathContext)
+	if !ok {
+		return nil
+	}
+	var methods = make(map[string]bool)
+	for _, child := range r.Children() {
+		if !isMethodRouter(child) {
+			continue
+		}
+		var _, has = child.RouterExcutor()
+		if has {
+			methods[strings.ToUpper(child.Name())] = true
+		}
+	}
+	if len(methods) <= 0 {
+		return nil
+	}
+	if methods["GET"] {
+		methods["HEAD"] = true
+	}
+	methods["OPTIONS"] = true
+	var result = make([]string, 0, len(methods))
+	for m := range methods {
+		result = append(result, m)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// matchHead 使用Get方法的路由处理Head请求,响应体被丢弃
+func (this *HttpProcessor) matchHead(context *Context) (router.RouterExcutor, bool) {
+	var segs = context.AllSegments()
+	var method = segs[len(segs)-1]
+	segs[len(segs)-1] = http.MethodGet
+	context.Level = 0
+	var executor, ok = this.rootOf(context).Match(context)
+	if !ok {
+		segs[len(segs)-1] = method
+		context.Level = 0
+		return nil, false
+	}
+	context.HttpContext.ResponseWriter = &headResponseWriter{context.HttpContext.ResponseWriter}
+	return executor, true
+}
+
+// Head请求的响应写入器,丢弃响应体
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write 丢弃响应体
+func (this *headResponseWriter) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+// Unwrap 返回原始的响应写入器,用于http.ResponseController
+func (this *headResponseWriter) Unwrap() http.ResponseWriter {
+	return this.ResponseWriter
+}
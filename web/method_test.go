@@ -0,0 +1,123 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// newMethodProcessor 创建/item允许Get和Put方法的处理器,gets记录Get路由的执行次数
+func newMethodProcessor(t *testing.T, gets *int) *HttpProcessor {
+	var root = NewRootRouter()
+	root.AddChild(NewFuncRouter("item", func(context *Context) GetResult {
+		*gets++
+		var result = context.Data([]byte("item"))
+		result.ContentType = "text/plain; charset=utf-8"
+		return result
+	}))
+	root.AddChild(NewFuncRouter("item", func(context *Context) PutResult {
+		return context.Data([]byte("put"))
+	}))
+	return newTestProcessor(t, root)
+}
+
+// serveMethod 使用method请求path
+func serveMethod(processor *HttpProcessor, method string, path string) *httptest.ResponseRecorder {
+	var w = httptest.NewRecorder()
+	testHandler(processor).ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	var gets = 0
+	var processor = newMethodProcessor(t, &gets)
+	var w = serveMethod(processor, "DELETE", "/item")
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET, HEAD, OPTIONS, PUT" {
+		t.Errorf("unexpected Allow %q", allow)
+	}
+	if w = serveMethod(processor, "PUT", "/item"); w.Code != 200 || w.Body.String() != "put" {
+		t.Errorf("PUT: unexpected response %d %q", w.Code, w.Body.String())
+	}
+	//路径不存在时返回404
+	for _, method := range []string{"GET", "DELETE", "OPTIONS"} {
+		if w = serveMethod(processor, method, "/missing"); w.Code != http.StatusNotFound {
+			t.Errorf("%s /missing: expected 404, got %d", method, w.Code)
+		}
+	}
+}
+
+func TestAutomaticOptions(t *testing.T) {
+	var gets = 0
+	var processor = newMethodProcessor(t, &gets)
+	var w = serveMethod(processor, "OPTIONS", "/item")
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Errorf("expected empty 204, got %d %q", w.Code, w.Body.String())
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET, HEAD, OPTIONS, PUT" {
+		t.Errorf("unexpected Allow %q", allow)
+	}
+	if gets != 0 {
+		t.Error("OPTIONS should not execute the GET route")
+	}
+}
+
+func TestHeadUsesGet(t *testing.T) {
+	var gets = 0
+	var processor = newMethodProcessor(t, &gets)
+	var w = serveMethod(processor, "HEAD", "/item")
+	if w.Code != 200 || w.Body.Len() != 0 {
+		t.Errorf("expected 200 without body, got %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Errorf("HEAD should keep GET headers, got %v", w.Header())
+	}
+	if gets != 1 {
+		t.Errorf("HEAD should execute the GET route once, got %d", gets)
+	}
+	var hw = &headResponseWriter{httptest.NewRecorder()}
+	if n, err := hw.Write([]byte("discard")); n != 7 || err != nil {
+		t.Errorf("headResponseWriter.Write = (%d, %v)", n, err)
+	}
+	if http.NewResponseController(hw).Flush() != nil {
+		t.Error("headResponseWriter should unwrap to a flushable writer")
+	}
+}
+
+func TestGetPostResult(t *testing.T) {
+	var root = NewRootRouter()
+	root.AddChild(NewFuncRouter("form", func(context *Context) GetPostResult {
+		return context.Data([]byte(context.HttpContext.Request.Method))
+	}))
+	var processor = newTestProcessor(t, root)
+	for _, method := range []string{"GET", "POST"} {
+		if w := serveMethod(processor, method, "/form"); w.Code != 200 || w.Body.String() != method {
+			t.Errorf("%s: unexpected response %d %q", method, w.Code, w.Body.String())
+		}
+	}
+	var w = serveMethod(processor, "DELETE", "/form")
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET, HEAD, OPTIONS, POST" {
+		t.Errorf("unexpected Allow %q", allow)
+	}
+}
+
+func TestHttpMethodName(t *testing.T) {
+	var cases = map[string][]string{
+		"web.GetResult":       {"Get"},
+		"web.GetPostResult":   {"Get", "Post"},
+		"web.PostResult":      {"Post"},
+		"web.PutDeleteResult": {"Put", "Delete"},
+		"Result":              {"Post"},
+	}
+	for name, expected := range cases {
+		if methods := HttpMethodName(name); !reflect.DeepEqual(methods, expected) {
+			t.Errorf("%s: expected %v, got %v", name, expected, methods)
+		}
+	}
+}
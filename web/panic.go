@@ -0,0 +1,96 @@
+package web
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/kdada/tinygo/connector"
+)
+
+// 请求处理过程中出现的panic
+type PanicError struct {
+	Request string      //请求行
+	Value   interface{} //recover()的返回值
+	Stack   []byte      //panic时的调用堆栈
+}
+
+// Error 返回错误信息
+func (this *PanicError) Error() string {
+	return ErrorPanic.Format(this.Request, this.Value).String()
+}
+
+// 错误页面信息,dev模式下包含错误详情和调用堆栈
+type ErrorPage struct {
+	XMLName xml.Name `json:"-" xml:"error"`
+	Status  int      `json:"status" xml:"status"`                   //Http状态码
+	Message string   `json:"message" xml:"message"`                 //错误信息
+	Stack   string   `json:"stack,omitempty" xml:"stack,omitempty"` //调用堆栈
+}
+
+// NewErrorPage 创建错误页面信息,dev模式下使用err作为错误信息,否则使用状态码对应的描述
+func NewErrorPage(config *HttpConfig, status StatusCode, err error) *ErrorPage {
+	var page = &ErrorPage{Status: int(status), Message: http.StatusText(int(status))}
+	if config.Mode == "dev" && err != nil {
+		page.Message = err.Error()
+		var e, ok = err.(*PanicError)
+		if ok {
+			page.Stack = string(e.Stack)
+		}
+	}
+	return page
+}
+
+// 错误页面模板
+var errorPageTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Status}} {{.Message}}</title></head>
+<body>
+<h1>{{.Status}}</h1>
+<p>{{.Message}}</p>
+{{if .Stack}}<pre>{{.Stack}}</pre>{{end}}
+</body>
+</html>
+`))
+
+// Html错误页面结果
+type ErrorPageResult struct {
+	CommonHttpResult
+	Page *ErrorPage //错误页面信息
+}
+
+// WriteTo 将Result的内容写入writer
+func (this *ErrorPageResult) WriteTo(writer io.Writer) error {
+	var w, err = this.SetHeader(writer)
+	if err != nil {
+		return err
+	}
+	this.WriteHeader(w)
+	return errorPageTemplate.Execute(w, this.Page)
+}
+
+// recoverPanic 记录panic的请求行和调用堆栈,并触发Panic事件
+//  http.ErrAbortHandler用于中止响应,会继续向上传递
+func (this *HttpProcessor) recoverPanic(ct *connector.HttpContext, context *Context, value interface{}) {
+	if value == http.ErrAbortHandler {
+		panic(value)
+	}
+	var r = ct.Request
+	var e = &PanicError{
+		Request: fmt.Sprintf("%s %s %s", r.Method, r.URL.RequestURI(), r.Proto),
+		Value:   value,
+		Stack:   debug.Stack(),
+	}
+	if this.Logger != nil {
+		this.Logger.Error(e.Error() + "\n" + string(e.Stack))
+	}
+	if this.Event != nil {
+		if context == nil {
+			context = &Context{HttpContext: ct, Processor: this}
+		}
+		this.Event.Panic(this, context, e)
+	}
+}
@@ -0,0 +1,113 @@
+package web
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// 记录Panic事件的处理器事件
+type testPanicEvent struct {
+	DefaultHttpProcessorEvent
+	err *PanicError
+}
+
+// Panic 记录panic并返回默认的错误页面
+func (this *testPanicEvent) Panic(processor *HttpProcessor, context *Context, err *PanicError) {
+	this.err = err
+	this.DefaultHttpProcessorEvent.Panic(processor, context, err)
+}
+
+// newPanicProcessor 创建/boom会panic的处理器
+func newPanicProcessor(t *testing.T, mode string, value interface{}) *HttpProcessor {
+	var root = NewRootRouter()
+	root.AddChild(NewFuncRouter("boom", func(context *Context) GetResult {
+		panic(value)
+	}))
+	return newTestProcessor(t, root, func(config *HttpConfig) {
+		config.Mode = mode
+	})
+}
+
+func TestRecoverPanic(t *testing.T) {
+	var processor = newPanicProcessor(t, "prod", "kaboom")
+	var event = new(testPanicEvent)
+	processor.Event = event
+	var w = httptest.NewRecorder()
+	testHandler(processor).ServeHTTP(w, httptest.NewRequest("GET", "/boom?a=1", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", w.Code)
+	}
+	if event.err == nil {
+		t.Fatal("Panic event was not called")
+	}
+	if event.err.Value != "kaboom" || event.err.Request != "GET /boom?a=1 HTTP/1.1" || len(event.err.Stack) == 0 {
+		t.Errorf("unexpected panic error %q %v", event.err.Request, event.err.Value)
+	}
+	//prod模式下不包含错误详情
+	var page ErrorPage
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if page.Status != 500 || page.Message != "Internal Server Error" || page.Stack != "" {
+		t.Errorf("unexpected prod error page %+v", page)
+	}
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+	var processor = newPanicProcessor(t, "prod", http.ErrAbortHandler)
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("expected http.ErrAbortHandler to be re-panicked, got %v", v)
+		}
+	}()
+	testHandler(processor).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/boom", nil))
+	t.Error("Dispatch should re-panic http.ErrAbortHandler")
+}
+
+func TestErrorPageNegotiation(t *testing.T) {
+	var processor = newPanicProcessor(t, "dev", "kaboom")
+	processor.Config.Api = "auto"
+	var cases = []struct {
+		accept      string
+		contentType string
+	}{
+		{"text/html,application/xhtml+xml", "text/html"},
+		{"application/xml", "application/xml"},
+		{"application/json", "application/json"},
+		{"", "application/json"},
+	}
+	for _, c := range cases {
+		var req = httptest.NewRequest("GET", "/boom", nil)
+		req.Header.Set("Accept", c.accept)
+		var w = httptest.NewRecorder()
+		testHandler(processor).ServeHTTP(w, req)
+		if w.Code != 500 || !strings.HasPrefix(w.Header().Get("Content-Type"), c.contentType) {
+			t.Errorf("%q: expected 500 %s, got %d %s", c.accept, c.contentType, w.Code, w.Header().Get("Content-Type"))
+			continue
+		}
+		//dev模式下包含错误详情和调用堆栈
+		var page ErrorPage
+		switch c.contentType {
+		case "text/html":
+			if !strings.Contains(w.Body.String(), "kaboom") || !strings.Contains(w.Body.String(), "<pre>") {
+				t.Errorf("html error page should contain the panic and stack: %s", w.Body.String())
+			}
+			continue
+		case "application/xml":
+			if err := xml.Unmarshal(w.Body.Bytes(), &page); err != nil {
+				t.Fatal(err)
+			}
+		default:
+			if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if page.Status != 500 || !strings.Contains(page.Message, "kaboom") || page.Stack == "" {
+			t.Errorf("%q: unexpected dev error page %+v", c.accept, page)
+		}
+	}
+}
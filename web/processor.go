@@ -1,9 +1,12 @@
 package web
 
 import (
+	"net"
 	"net/http"
 	"path/filepath"
 	"reflect"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/kdada/tinygo/connector"
@@ -19,7 +22,8 @@ type ParamTypeFunc func(context *Context, name string, t reflect.Type) interface
 
 // HttpProcessor 用于协调http连接器和路由,并管理Http应用的所有内容
 type HttpProcessor struct {
-	Root                  router.Router                 //根路由
+	Root                  router.Router                 //根路由,虚拟主机都不匹配时使用
+	Hosts                 []*VirtualHost                //按匹配顺序排列的虚拟主机
 	Config                *HttpConfig                   //http配置
 	Logger                log.Logger                    //日志记录
 	SessionContainer      session.SessionContainer      //Session容器
@@ -28,7 +32,12 @@ type HttpProcessor struct {
 	MutiTypeFinders       []ContextValueFinder          //Context多类型值查找器
 	DefaultValueContainer meta.ValueContainer           //web执行器默认使用的值容器
 	Templates             *template.ViewTemplates       //视图模板信息
+	TrustedProxies        []*net.IPNet                  //受信任的代理
 	Event                 HttpProcessorEvent            //处理器事件
+	wsMu                  sync.Mutex                    //保护webSockets和wsClosed
+	webSockets            map[*WebSocketConn]bool       //正在处理的WebSocket连接
+	wsClosed              bool                          //是否已经关闭全部WebSocket连接
+	wsWait                sync.WaitGroup                //等待WebSocket处理方法返回
 }
 
 // NewHttpProcessor 创建Http处理器
@@ -62,6 +71,13 @@ func NewHttpProcessor(root router.Router, config *HttpConfig) (*HttpProcessor, e
 		processor.CSRFContainer = container
 	}
 
+	//受信任的代理
+	var proxies, err = ParseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	processor.TrustedProxies = proxies
+
 	//注册参数类型方法
 	processor.Finders = make(map[string]ContextValueFinder)
 	processor.MutiTypeFinders = make([]ContextValueFinder, 0, 1)
@@ -70,6 +86,8 @@ func NewHttpProcessor(root router.Router, config *HttpConfig) (*HttpProcessor, e
 	processor.DefaultValueContainer = meta.GlobalValueContainer
 	//创建视图模板信息
 	processor.Templates = template.NewViewTemplates(config.TemplateConfig)
+	//模板中的urlfor使用当前处理器生成url
+	processor.Templates.SetURLBuilder(processor.URLFor)
 	if config.Precompile {
 		//预编译模板
 		var err = processor.Templates.CompileAll()
@@ -95,7 +113,12 @@ func NewHttpProcessor(root router.Router, config *HttpConfig) (*HttpProcessor, e
 	if processor.Config.Home != "" {
 		var r = NewSpaceRouter("Get")
 		var excutor = NewSimpleExecutor(func(r *Context) (interface{}, error) {
-			return r.Redispatch(r.Processor.Config.Home), nil
+			//Home可以是路由别名
+			var home, err = r.Processor.URLFor(r.Processor.Config.Home, nil)
+			if err != nil {
+				home = r.Processor.Config.Home
+			}
+			return r.Redispatch(home), nil
 		})
 		r.SetRouterExcutorGenerator(func() router.RouterExcutor {
 			return excutor
@@ -105,6 +128,23 @@ func NewHttpProcessor(root router.Router, config *HttpConfig) (*HttpProcessor, e
 	return processor, nil
 }
 
+// URLFor 根据路由别名生成url,先查找Root,然后按匹配顺序查找虚拟主机的根路由
+//  alias:路由别名,控制器方法的默认别名为"控制器名.方法名",函数路由的默认别名为路由名称
+//  params:路由值,正则路由使用的值必须匹配相应的正则表达式,其余的值作为查询参数
+func (this *HttpProcessor) URLFor(alias string, params map[string]string) (string, error) {
+	var r, err = router.FindAlias(this.Root, alias)
+	for i := 0; err != nil && i < len(this.Hosts); i++ {
+		var hr, e = router.FindAlias(this.Hosts[i].Root, alias)
+		if e == nil {
+			r, err = hr, nil
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return router.BuildURL(r, params)
+}
+
 // RegisterFinder 注册单一类型的值查找器
 func (this *HttpProcessor) RegisterFinder(t reflect.Type, finder ContextValueFinder) {
 	this.Finders[t.String()] = finder
@@ -120,6 +160,19 @@ func (this *HttpProcessor) SetDefaultValueContainer(container meta.ValueContaine
 	this.DefaultValueContainer = container
 }
 
+// Close 关闭Session容器,CSRF容器和日志,关闭后处理器无法再使用这些资源
+func (this *HttpProcessor) Close() {
+	if this.SessionContainer != nil {
+		this.SessionContainer.Close()
+	}
+	if this.CSRFContainer != nil {
+		this.CSRFContainer.Close()
+	}
+	if this.Logger != nil {
+		this.Logger.Close()
+	}
+}
+
 // createCookie 创建cookie
 func (this *HttpProcessor) createCookie(name string, id string, expire int) *http.Cookie {
 	var cookieValue = new(http.Cookie)
@@ -146,7 +199,9 @@ func (this *HttpProcessor) ResolveSession(context *Context) {
 		}
 		if !ok {
 			ss, ok = this.SessionContainer.CreateSession()
-			context.AddCookie(this.createCookie(this.Config.SessionCookieName, ss.SessionId(), this.Config.SessionCookieExpire))
+			if ok {
+				context.AddCookie(this.createCookie(this.Config.SessionCookieName, ss.SessionId(), this.Config.SessionCookieExpire))
+			}
 		}
 		if ok {
 			context.Session = ss
@@ -162,7 +217,9 @@ func (this *HttpProcessor) ResolveSession(context *Context) {
 		}
 		if !ok {
 			ss, ok = this.CSRFContainer.CreateSession()
-			context.AddCookie(this.createCookie(this.Config.CSRFCookieName, ss.SessionId(), this.Config.CSRFCookieExpire))
+			if ok {
+				context.AddCookie(this.createCookie(this.Config.CSRFCookieName, ss.SessionId(), this.Config.CSRFCookieExpire))
+			}
 		}
 		if ok {
 			context.CSRF = ss
@@ -170,12 +227,33 @@ func (this *HttpProcessor) ResolveSession(context *Context) {
 	}
 }
 
+// writeHSTS 在https响应中添加Strict-Transport-Security头
+func (this *HttpProcessor) writeHSTS(context *connector.HttpContext) {
+	if this.Config.HSTSMaxAge <= 0 || context.Request.TLS == nil {
+		return
+	}
+	var value = "max-age=" + strconv.Itoa(this.Config.HSTSMaxAge)
+	if this.Config.HSTSSubDomains {
+		value += "; includeSubDomains"
+	}
+	context.ResponseWriter.Header().Set("Strict-Transport-Security", value)
+}
+
 // Dispatch 将接收到的请求进行分发
 //  segments:用于进行分发的路径段信息
 //  data:连接携带的数据
 func (this *HttpProcessor) Dispatch(segments []string, data interface{}) {
 	var ct = data.(*connector.HttpContext)
-	var context, err = NewContext(segments, ct, this)
+	var context *Context
+	defer func() {
+		var v = recover()
+		if v != nil {
+			this.recoverPanic(ct, context, v)
+		}
+	}()
+	this.writeHSTS(ct)
+	var err error
+	context, err = NewContext(segments, ct, this)
 	if err == nil {
 		this.ResolveSession(context)
 		if this.Event != nil {
@@ -186,9 +264,38 @@ func (this *HttpProcessor) Dispatch(segments []string, data interface{}) {
 			}
 		}
 		//路由匹配
-		var executor, ok = this.Root.Match(context)
+		context.Root = this.RootFor(context)
+		var executor, ok = context.Root.Match(context)
+		var method = ct.Request.Method
+		if !ok && method == http.MethodHead {
+			//Head请求使用Get方法的路由处理
+			executor, ok = this.matchHead(context)
+		}
+		if !ok {
+			var allow = this.AllowedMethods(context)
+			if len(allow) > 0 {
+				if method == http.MethodOptions {
+					//自动响应Options请求
+					if this.Event != nil {
+						this.Event.RequestFinish(this, context, []interface{}{context.Options(allow)})
+					}
+				} else if this.Event != nil {
+					this.Event.Error(this, context, &MethodNotAllowedError{ct.Request.URL.Path, method, allow})
+				}
+				return
+			}
+		}
 		if ok {
-			var r, err = executor.Execute()
+			//路由组配置和multipart表单
+			context.Group = groupOf(executor.Router())
+			var err = context.ParseMultipartForm()
+			if err != nil {
+				if this.Event != nil {
+					this.Event.Error(this, context, err)
+				}
+				return
+			}
+			r, err := executor.Execute()
 			if err != nil {
 				this.Event.Error(this, context, err)
 			} else if this.Event != nil {
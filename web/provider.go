@@ -2,6 +2,8 @@ package web
 
 import (
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/kdada/tinygo/meta"
 )
@@ -28,17 +30,38 @@ func (this *ContextValueProvider) Value() interface{} {
 // 优先级:web.Processor.Finders > web.Processor.MutiTypeFinders > web.Processor.ValueContainer
 type ContextValueContainer struct {
 	Context *Context
+	Prefix  string //嵌套字段的前缀,例如Address或Items.0,顶层值容器为空
 }
 
 // NewContextValueContainer 创建http上下文值容器
 func NewContextValueContainer(context *Context) *ContextValueContainer {
 	return &ContextValueContainer{
 		context,
+		"",
 	}
 }
 
+// Nested 返回名称为name的嵌套字段的值容器
+func (this *ContextValueContainer) Nested(name string) meta.NestedValueContainer {
+	return &ContextValueContainer{this.Context, this.path(name)}
+}
+
+// Keys 返回名称为name的嵌套字段包含的子字段名称,切片索引或map的key
+func (this *ContextValueContainer) Keys(name string) []string {
+	return this.Context.formChildren(this.path(name))
+}
+
+// path 返回name在表单中的完整路径
+func (this *ContextValueContainer) path(name string) string {
+	if this.Prefix == "" {
+		return name
+	}
+	return this.Prefix + "." + name
+}
+
 // String 根据名称和类型返回相应的字符串值,返回的bool表示该值是否存在
 func (this *ContextValueContainer) Contains(name string, t reflect.Type) (meta.ValueProvider, bool) {
+	name = this.path(name)
 	// 查找 web.Processor.Finders
 	var finder, ok = this.Context.Processor.Finders[t.String()]
 	if ok {
@@ -68,3 +91,64 @@ func (this *ContextValueContainer) Contains(name string, t reflect.Type) (meta.V
 	}
 	return nil, false
 }
+
+// normalizeFormKey 规范化表单key,[]后缀被移除,[key]转换为.key,例如Items[0][Qty]和Items[0].Qty都转换为Items.0.Qty
+func normalizeFormKey(key string) string {
+	key = strings.TrimSuffix(key, "[]")
+	if !strings.Contains(key, "[") {
+		return key
+	}
+	var replacer = strings.NewReplacer("][", ".", "[", ".", "]", "")
+	return replacer.Replace(key)
+}
+
+// formIndex 返回规范化的表单key到原始key的映射
+func (this *Context) formIndex() map[string][]string {
+	if this.formKeys == nil {
+		this.formKeys = make(map[string][]string)
+		for k := range this.HttpContext.Request.Form {
+			var n = normalizeFormKey(k)
+			this.formKeys[n] = append(this.formKeys[n], k)
+		}
+		for _, keys := range this.formKeys {
+			sort.Strings(keys)
+		}
+	}
+	return this.formKeys
+}
+
+// formValues 返回规范化的key为path的所有表单值
+func (this *Context) formValues(path string) ([]string, bool) {
+	var keys, ok = this.formIndex()[path]
+	if !ok {
+		return nil, false
+	}
+	var result = make([]string, 0)
+	for _, k := range keys {
+		result = append(result, this.HttpContext.Request.Form[k]...)
+	}
+	return result, true
+}
+
+// formChildren 返回规范化的key以path.开头的表单值的下一级名称
+func (this *Context) formChildren(path string) []string {
+	var prefix = path + "."
+	var exist = make(map[string]bool)
+	var result = make([]string, 0)
+	for k := range this.formIndex() {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		var child = k[len(prefix):]
+		var pos = strings.Index(child, ".")
+		if pos >= 0 {
+			child = child[:pos]
+		}
+		if child != "" && !exist[child] {
+			exist[child] = true
+			result = append(result, child)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
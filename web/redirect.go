@@ -0,0 +1,51 @@
+package web
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kdada/tinygo/connector"
+)
+
+// https重定向调度器,将全部http请求重定向到相同主机的https地址
+type HttpsRedirector struct {
+	Port int //https端口
+}
+
+// NewHttpsRedirector 创建https重定向调度器
+//  port:https端口,为443时重定向地址中不包含端口
+func NewHttpsRedirector(port int) *HttpsRedirector {
+	return &HttpsRedirector{port}
+}
+
+// Dispatch 分发
+func (this *HttpsRedirector) Dispatch(segments []string, data interface{}) {
+	var context = data.(*connector.HttpContext)
+	var r = context.Request
+	var host = r.Host
+	if host == "" {
+		http.Error(context.ResponseWriter, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	var hostname, _, err = net.SplitHostPort(host)
+	if err != nil {
+		//没有端口
+		hostname = strings.Trim(host, "[]")
+	}
+	if this.Port != 443 {
+		host = net.JoinHostPort(hostname, strconv.Itoa(this.Port))
+	} else if strings.Contains(hostname, ":") {
+		//ipv6地址需要加上方括号
+		host = "[" + hostname + "]"
+	} else {
+		host = hostname
+	}
+	//GET和HEAD以外的请求使用308,保证浏览器重定向后不改变请求方法
+	var code = http.StatusMovedPermanently
+	if r.Method != "GET" && r.Method != "HEAD" {
+		code = http.StatusPermanentRedirect
+	}
+	http.Redirect(context.ResponseWriter, r, "https://"+host+r.URL.RequestURI(), code)
+}
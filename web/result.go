@@ -216,6 +216,40 @@ func (this *NotFoundResult) WriteTo(writer io.Writer) error {
 	return nil
 }
 
+// 405结果
+type MethodNotAllowedResult struct {
+	CommonHttpResult
+	Allow []string //允许的Http方法
+}
+
+// WriteTo 将Result的内容写入writer
+func (this *MethodNotAllowedResult) WriteTo(writer io.Writer) error {
+	var w, err = this.SetHeader(writer)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Allow", strings.Join(this.Allow, ", "))
+	http.Error(w, http.StatusText(int(this.Status)), int(this.Status))
+	return nil
+}
+
+// Options结果,返回允许的Http方法
+type AllowedMethodsResult struct {
+	CommonHttpResult
+	Allow []string //允许的Http方法
+}
+
+// WriteTo 将Result的内容写入writer
+func (this *AllowedMethodsResult) WriteTo(writer io.Writer) error {
+	var w, err = this.SetHeader(writer)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Allow", strings.Join(this.Allow, ", "))
+	this.WriteHeader(w)
+	return nil
+}
+
 // 重定向结果
 type RedirectResult struct {
 	CommonHttpResult
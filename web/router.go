@@ -69,6 +69,16 @@ func NewRootRouter() router.Router {
 	return NewSpaceRouter("")
 }
 
+// NewTreeRootRouter 创建适用于Web App的树路由根路由,全部子路由编译为前缀树,匹配顺序确定并且在路由较多时更快
+//  同一级的子路由冲突时添加路由会panic,冲突规则参考router.TreeRouter
+func NewTreeRootRouter() router.Router {
+	var r, err = router.NewRouter("tree", "", "")
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
 // NewControllerRouter 创建控制器路由,根据方法返回值确定该方法处理哪种形式的http请求
 //  instance:控制器对象
 //  控制器方法必须满足如下格式:
@@ -76,7 +86,7 @@ func NewRootRouter() router.Router {
 //   this:必须是控制器指针
 //   param:可以没有或者有多个,如果有则类型必须为结构体指针类型
 //   第一个返回结果最好是能够赋值给web.Result接口,也可以是其他类型
-//  return:执行成功则返回控制器的router.Router
+//  return:执行成功则返回控制器的router.Router,方法路由的别名为"控制器名.方法名",可用于生成url
 func NewControllerRouter(instance interface{}) router.Router {
 	var instanceType = reflect.TypeOf(instance)
 	if !meta.IsStructPtrType(instanceType) {
@@ -105,7 +115,9 @@ func NewControllerRouter(instance interface{}) router.Router {
 	var controllerRouter = NewSpaceRouter(controllerName)
 	for _, m := range methods {
 		var mr = NewSpaceRouter(m.Name)
+		mr.SetAlias(controllerName + "." + m.Name)
 		var excutor = NewAdvancedExecutor(m)
+		excutor.kind = "controller"
 		mr.AddChildren(HttpResultRouter(m.Return[0].Name(), func() router.RouterExcutor {
 			return excutor
 		}))
@@ -118,7 +130,7 @@ func NewControllerRouter(instance interface{}) router.Router {
 //  instance:控制器对象
 //  name:控制器路由名称,为空时使用instance类名(不含Controller)
 //  methodsInfo:路由方法信息数组,RouterName为空时使用方法名
-//  return:执行成功则返回控制器的router.Router
+//  return:执行成功则返回控制器的router.Router,方法路由的别名为"name.方法名",可用于生成url
 func NewCustomControllerRouter(instance interface{}, name string, methodsInfo []RouterMethod) router.Router {
 	var instanceType = reflect.TypeOf(instance)
 	if !meta.IsStructPtrType(instanceType) {
@@ -149,7 +161,9 @@ func NewCustomControllerRouter(instance interface{}, name string, methodsInfo []
 			rname = m.Name
 		}
 		var mr = NewSpaceRouter(rname)
+		mr.SetAlias(name + "." + info.MethodName)
 		var excutor = NewAdvancedExecutor(m)
+		excutor.kind = "controller"
 		mr.AddChildren(HttpResultRouter(string(info.HttpMethod), func() router.RouterExcutor {
 			return excutor
 		}))
@@ -159,7 +173,7 @@ func NewCustomControllerRouter(instance interface{}, name string, methodsInfo []
 }
 
 // NewFuncRouter 创建函数路由,根据方法返回值确定该方法处理哪种形式的http请求
-//  name:路由名称
+//  name:路由名称,同时作为路由别名
 //  function:函数
 //  函数必须满足如下格式:
 //   func Method(param *ParamStruct) web.Result
@@ -173,6 +187,7 @@ func NewFuncRouter(name string, function interface{}) router.Router {
 		panic(err)
 	}
 	var mr = NewSpaceRouter(name)
+	mr.SetAlias(name)
 	var excutor = NewAdvancedExecutor(mMd)
 	var mName = ""
 	if CheckResult(mMd) == nil {
@@ -185,7 +200,7 @@ func NewFuncRouter(name string, function interface{}) router.Router {
 }
 
 // NewMutableFuncRouter 创建函数路由,可匹配无限层级和任意http方法的请求
-//  name:路由名称
+//  name:路由名称,同时作为路由别名
 //  function:函数
 //  函数必须满足如下格式:
 //   func Method(param *ParamStruct) web.Result
@@ -202,6 +217,7 @@ func NewMutableFuncRouter(name string, function interface{}) router.Router {
 	if err2 != nil {
 		panic(err2)
 	}
+	mr.SetAlias(name)
 	var excutor = NewAdvancedExecutor(mMd)
 	mr.SetRouterExcutorGenerator(func() router.RouterExcutor {
 		return excutor
@@ -210,11 +226,12 @@ func NewMutableFuncRouter(name string, function interface{}) router.Router {
 }
 
 // NewFileRouter 创建文件路由,只能匹配Get类型的文件请求,返回指定的文件
-//  name:路由名称
+//  name:路由名称,同时作为路由别名
 //  path:文件路径
 //  return:执行成功则返回router.Router
 func NewFileRouter(name string, path string) router.Router {
 	var mr = NewSpaceRouter(name)
+	mr.SetAlias(name)
 	var excutor = NewFileExecutor(path)
 	mr.AddChildren(HttpResultRouter("Get", func() router.RouterExcutor {
 		return excutor
@@ -223,7 +240,7 @@ func NewFileRouter(name string, path string) router.Router {
 }
 
 // NewStaticRouter 创建静态文件路由,只能匹配Get类型的文件请求,返回指定的文件
-//  name:路由名称
+//  name:路由名称,同时作为路由别名
 //  path:文件目录路径
 //  return:执行成功则返回router.Router
 func NewStaticRouter(name string, path string) router.Router {
@@ -231,6 +248,7 @@ func NewStaticRouter(name string, path string) router.Router {
 	if err != nil {
 		panic(err)
 	}
+	mr.SetAlias(name)
 	var excutor = NewStaticExecutor(path)
 	mr.SetRouterExcutorGenerator(func() router.RouterExcutor {
 		return excutor
@@ -240,6 +258,20 @@ func NewStaticRouter(name string, path string) router.Router {
 	return pathRouter
 }
 
+// NewWebSocketRouter 创建WebSocket路由,只能匹配Get请求,过滤器执行后进行握手并使用handler处理连接
+//  name:路由名称,同时作为路由别名
+//  handler:连接处理方法,handler返回后连接将被关闭
+//  return:执行成功则返回router.Router
+func NewWebSocketRouter(name string, handler WebSocketHandler) router.Router {
+	var mr = NewSpaceRouter(name)
+	mr.SetAlias(name)
+	var excutor = NewWebSocketExecutor(handler)
+	mr.AddChildren(HttpResultRouter("Get", func() router.RouterExcutor {
+		return excutor
+	}))
+	return mr
+}
+
 // CheckResult 检查元数据的第一个返回值是否符合web.Result接口
 func CheckResult(m *meta.MethodMetadata) error {
 	if len(m.Return) <= 0 {
@@ -262,6 +294,9 @@ func HttpMethodName(name string) []string {
 	if strings.Contains(name, "Get") {
 		result = append(result, "Get")
 	}
+	if strings.Contains(name, "Post") {
+		result = append(result, "Post")
+	}
 	if strings.Contains(name, "Put") {
 		result = append(result, "Put")
 	}
@@ -0,0 +1,178 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kdada/tinygo/router"
+)
+
+// 可以描述自身的执行器,用于生成路由列表
+type DescribedExecutor interface {
+	// Describe 返回执行器类型和执行目标
+	//  类型:controller(控制器方法),func(函数),static(静态目录),file(文件),websocket
+	//  执行目标:方法名称,函数名称或者文件路径
+	Describe() (string, string)
+}
+
+// 路由列表项
+type RouteInfo struct {
+	Host          string   `json:"host,omitempty"` //虚拟主机匹配模式,Root中的路由为空
+	Method        string   `json:"method"`         //Http方法,*表示匹配任意方法
+	Path          string   `json:"path"`           //路径模式,正则路由使用原始匹配字符串,无限路由使用*
+	Alias         string   `json:"alias"`          //路由别名
+	Keys          []string `json:"keys"`           //正则路由能够提取的路由值名称
+	Executor      string   `json:"executor"`       //执行器类型
+	Target        string   `json:"target"`         //执行目标
+	PreFilters    []string `json:"preFilters"`     //按执行顺序排列的前置过滤器
+	PostFilters   []string `json:"postFilters"`    //按执行顺序排列的后置过滤器
+	AroundFilters []string `json:"aroundFilters"`  //由外到内排列的环绕过滤器
+}
+
+// Routes 返回root及其子路由中所有可以访问的路由
+func Routes(root router.Router) []*RouteInfo {
+	var routes = router.Routes(root)
+	var result = make([]*RouteInfo, 0, len(routes))
+	for _, route := range routes {
+		var info = &RouteInfo{
+			Method:        "*",
+			Path:          route.Pattern(),
+			Alias:         route.Router.Alias(),
+			Keys:          route.Keys,
+			PreFilters:    make([]string, 0, len(route.PreFilters)),
+			PostFilters:   make([]string, 0, len(route.PostFilters)),
+			AroundFilters: make([]string, 0, len(route.AroundFilters)),
+		}
+		var chain = route.Chain
+		if len(chain) > 1 && isMethodRouter(route.Router) {
+			//Http方法路由,路径和别名由上一级路由确定
+			info.Method = strings.ToUpper(route.Router.Name())
+			info.Path = router.Pattern(chain[:len(chain)-1])
+			info.Alias = chain[len(chain)-2].Alias()
+		}
+		var described, ok = route.Executor.(DescribedExecutor)
+		if ok {
+			info.Executor, info.Target = described.Describe()
+		} else {
+			info.Executor = reflect.TypeOf(route.Executor).String()
+		}
+		for _, f := range route.PreFilters {
+			info.PreFilters = append(info.PreFilters, filterName(f))
+		}
+		for _, f := range route.PostFilters {
+			info.PostFilters = append(info.PostFilters, filterName(f))
+		}
+		for _, f := range route.AroundFilters {
+			info.AroundFilters = append(info.AroundFilters, filterName(f))
+		}
+		result = append(result, info)
+	}
+	return result
+}
+
+// isMethodRouter 判断r是否为HttpResultRouter生成的Http方法路由
+func isMethodRouter(r router.Router) bool {
+	if !r.Normal() || r.Name() != r.MatchString() {
+		return false
+	}
+	switch HttpMethod(r.Name()) {
+	case HttpMethodGet, HttpMethodPost, HttpMethodPut, HttpMethodDelete,
+		HttpMethodOptions, HttpMethodHead, HttpMethodTrace, HttpMethodConnect:
+		return true
+	}
+	return false
+}
+
+// filterName 返回过滤器名称,过滤器实现了fmt.Stringer时使用String()
+func filterName(filter interface{}) string {
+	var s, ok = filter.(fmt.Stringer)
+	if ok {
+		return s.String()
+	}
+	return reflect.TypeOf(filter).String()
+}
+
+// WriteRoutes 将路由列表写入writer
+//  format:输出格式,json为json数组,其他为文本表格
+func WriteRoutes(writer io.Writer, routes []*RouteInfo, format string) error {
+	if format == "json" {
+		var bytes, err = json.MarshalIndent(routes, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(append(bytes, '\n'))
+		return err
+	}
+	var w = tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	//存在虚拟主机路由时输出HOST列
+	var host = false
+	for _, r := range routes {
+		host = host || r.Host != ""
+	}
+	if host {
+		fmt.Fprint(w, "HOST\t")
+	}
+	fmt.Fprintln(w, "METHOD\tPATH\tALIAS\tKEYS\tEXECUTOR\tTARGET\tAROUND FILTERS\tPRE FILTERS\tPOST FILTERS")
+	for _, r := range routes {
+		if host {
+			fmt.Fprintf(w, "%s\t", tableCell(r.Host))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Method, r.Path, tableCell(r.Alias),
+			tableCell(strings.Join(r.Keys, ",")), r.Executor, tableCell(r.Target), tableCell(strings.Join(r.AroundFilters, ",")),
+			tableCell(strings.Join(r.PreFilters, ",")), tableCell(strings.Join(r.PostFilters, ",")))
+	}
+	return w.Flush()
+}
+
+// tableCell 空内容在表格中显示为-
+func tableCell(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// Routes 返回处理器根路由和虚拟主机中所有可以访问的路由
+func (this *HttpProcessor) Routes() []*RouteInfo {
+	var result = Routes(this.Root)
+	for _, h := range this.Hosts {
+		for _, r := range Routes(h.Root) {
+			r.Host = h.Pattern
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// NewRoutesRouter 创建路由列表路由,只能匹配Get请求,返回处理器中所有可以访问的路由
+//  请求参数format为json时返回json数组,否则返回文本表格
+//  路由列表会暴露应用的全部接口,应当只在调试时使用或者添加过滤器限制访问
+//  name:路由名称,同时作为路由别名
+//  return:执行成功则返回router.Router
+func NewRoutesRouter(name string) router.Router {
+	var mr = NewSpaceRouter(name)
+	mr.SetAlias(name)
+	var excutor = NewSimpleExecutor(func(context *Context) (interface{}, error) {
+		var routes = context.Processor.Routes()
+		var format, _ = context.ParamString("format")
+		if format == "json" {
+			return context.Json(routes), nil
+		}
+		var builder = new(strings.Builder)
+		var err = WriteRoutes(builder, routes, format)
+		if err != nil {
+			return nil, err
+		}
+		var result = context.Data([]byte(builder.String()))
+		result.ContentType = "text/plain; charset=utf-8"
+		return result, nil
+	})
+	mr.AddChildren(HttpResultRouter("Get", func() router.RouterExcutor {
+		return excutor
+	}))
+	return mr
+}
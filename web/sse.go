@@ -0,0 +1,180 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 默认的心跳间隔
+const DefaultSSEHeartbeat = 15 * time.Second
+
+// 事件流处理方法,方法返回后事件流结束
+type SSEHandler func(stream *SSEStream, context *Context)
+
+// 服务端事件
+type SSEEvent struct {
+	Id    string        //事件id,客户端重连时通过Last-Event-ID返回最后收到的id,可以为空
+	Event string        //事件类型,为空时客户端触发message事件
+	Data  string        //事件数据,可以包含多行
+	Retry time.Duration //客户端断开后的重连等待时间,为0时不设置
+}
+
+// 事件流
+type SSEStream struct {
+	writer      http.ResponseWriter      //http响应
+	controller  *http.ResponseController //用于刷新缓冲区
+	lastEventId string                   //客户端重连时携带的Last-Event-ID
+	mu          sync.Mutex               //保证事件的写入不会交错
+	err         error                    //写入出错后保存错误
+	done        chan struct{}            //客户端断开连接后关闭
+	doneOnce    sync.Once                //保证done只关闭一次
+}
+
+// LastEventId 返回客户端重连时携带的Last-Event-ID,首次连接时为空
+func (this *SSEStream) LastEventId() string {
+	return this.lastEventId
+}
+
+// Done 返回客户端断开连接时关闭的通道
+func (this *SSEStream) Done() <-chan struct{} {
+	return this.done
+}
+
+// Send 发送事件并立即刷新
+func (this *SSEStream) Send(event *SSEEvent) error {
+	var buf = make([]byte, 0, len(event.Data)+32)
+	if event.Id != "" {
+		buf = append(buf, "id: "+sseLine(event.Id)+"\n"...)
+	}
+	if event.Event != "" {
+		buf = append(buf, "event: "+sseLine(event.Event)+"\n"...)
+	}
+	if event.Retry > 0 {
+		buf = append(buf, "retry: "+strconv.FormatInt(int64(event.Retry/time.Millisecond), 10)+"\n"...)
+	}
+	var data = strings.Replace(event.Data, "\r\n", "\n", -1)
+	for _, line := range strings.Split(strings.Replace(data, "\r", "\n", -1), "\n") {
+		buf = append(buf, "data: "+line+"\n"...)
+	}
+	return this.write(append(buf, '\n'))
+}
+
+// SendData 发送只包含数据的事件
+func (this *SSEStream) SendData(data string) error {
+	return this.Send(&SSEEvent{Data: data})
+}
+
+// SendEvent 发送指定类型的事件
+func (this *SSEStream) SendEvent(event string, data string) error {
+	return this.Send(&SSEEvent{Event: event, Data: data})
+}
+
+// Comment 发送注释,客户端会忽略注释,可以用于保持连接
+func (this *SSEStream) Comment(text string) error {
+	return this.write([]byte(": " + sseLine(text) + "\n\n"))
+}
+
+// write 写入数据并刷新,出错时认为客户端已经断开
+func (this *SSEStream) write(data []byte) error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if this.err != nil {
+		return this.err
+	}
+	var _, err = this.writer.Write(data)
+	if err == nil {
+		err = this.controller.Flush()
+	}
+	if err != nil {
+		this.err = err
+		this.close()
+	}
+	return err
+}
+
+// finish 结束事件流,之后的写入都返回错误
+func (this *SSEStream) finish() {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if this.err == nil {
+		this.err = ErrorEventStreamClosed.Error()
+	}
+	this.close()
+}
+
+// close 关闭done
+func (this *SSEStream) close() {
+	this.doneOnce.Do(func() {
+		close(this.done)
+	})
+}
+
+// sseLine 去掉字段中的换行符
+func sseLine(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
+
+// 事件流结果,写入时保持连接并使用Handler发送事件
+type SSEResult struct {
+	CommonHttpResult
+	Context   *Context      //请求上下文
+	Handler   SSEHandler    //事件流处理方法
+	Heartbeat time.Duration //心跳注释的发送间隔,为0时不发送
+}
+
+// WriteTo 设置事件流响应头并调用Handler,Handler返回或者客户端断开后结束
+func (this *SSEResult) WriteTo(writer io.Writer) error {
+	var w, err = this.SetHeader(writer)
+	if err != nil {
+		return err
+	}
+	var r = this.Context.HttpContext.Request
+	var header = w.Header()
+	header.Set("Cache-Control", "no-cache")
+	//禁止nginx缓冲响应
+	header.Set("X-Accel-Buffering", "no")
+	var stream = &SSEStream{
+		writer:      w,
+		controller:  http.NewResponseController(w),
+		lastEventId: r.Header.Get("Last-Event-ID"),
+		done:        make(chan struct{}),
+	}
+	//事件流不受http服务写入超时的限制
+	stream.controller.SetWriteDeadline(time.Time{})
+	this.WriteHeader(w)
+	err = stream.controller.Flush()
+	if err != nil {
+		return err
+	}
+	var finished = make(chan struct{})
+	go func() {
+		var heartbeat <-chan time.Time
+		if this.Heartbeat > 0 {
+			var ticker = time.NewTicker(this.Heartbeat)
+			defer ticker.Stop()
+			heartbeat = ticker.C
+		}
+		for {
+			select {
+			case <-heartbeat:
+				stream.Comment("heartbeat")
+			case <-r.Context().Done():
+				//客户端断开连接
+				stream.close()
+				return
+			case <-stream.done:
+				return
+			case <-finished:
+				return
+			}
+		}
+	}()
+	this.Handler(stream, this.Context)
+	close(finished)
+	stream.finish()
+	return nil
+}
@@ -0,0 +1,107 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSEFraming(t *testing.T) {
+	var lastEventId string
+	var stream *SSEStream
+	var root = NewRootRouter()
+	root.AddChild(NewFuncRouter("events", func(context *Context) GetResult {
+		return context.EventStream(func(s *SSEStream, context *Context) {
+			stream = s
+			lastEventId = s.LastEventId()
+			s.Send(&SSEEvent{Id: "42\n", Event: "tick", Data: "a\nb\r\nc\rd", Retry: 1500 * time.Millisecond})
+			s.SendData("x")
+			s.SendEvent("done", "")
+			s.Comment("c\nd")
+		})
+	}))
+	var processor = newTestProcessor(t, root)
+	var req = httptest.NewRequest("GET", "/events", nil)
+	req.Header.Set("Last-Event-ID", "41")
+	var w = httptest.NewRecorder()
+	testHandler(processor).ServeHTTP(w, req)
+	if w.Code != 200 || w.Header().Get("Content-Type") != "text/event-stream; charset=utf-8" ||
+		w.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("unexpected response %d %v", w.Code, w.Header())
+	}
+	var expected = "id: 42\nevent: tick\nretry: 1500\ndata: a\ndata: b\ndata: c\ndata: d\n\n" +
+		"data: x\n\n" +
+		"event: done\ndata: \n\n" +
+		": cd\n\n"
+	if w.Body.String() != expected {
+		t.Errorf("unexpected body:\n%q\nexpected:\n%q", w.Body.String(), expected)
+	}
+	if lastEventId != "41" {
+		t.Errorf("expected Last-Event-ID 41, got %q", lastEventId)
+	}
+	//处理方法返回后事件流结束
+	if err := stream.SendData("late"); err == nil {
+		t.Error("send after the handler returned should fail")
+	}
+	select {
+	case <-stream.Done():
+	default:
+		t.Error("Done should be closed after the handler returned")
+	}
+}
+
+func TestSSEHeartbeat(t *testing.T) {
+	var root = NewRootRouter()
+	root.AddChild(NewFuncRouter("events", func(context *Context) GetResult {
+		var result = context.EventStream(func(s *SSEStream, context *Context) {
+			time.Sleep(100 * time.Millisecond)
+		})
+		result.Heartbeat = 20 * time.Millisecond
+		return result
+	}))
+	var processor = newTestProcessor(t, root)
+	var w = httptest.NewRecorder()
+	testHandler(processor).ServeHTTP(w, httptest.NewRequest("GET", "/events", nil))
+	if n := strings.Count(w.Body.String(), ": heartbeat\n\n"); n < 2 {
+		t.Errorf("expected at least 2 heartbeats, got %d in %q", n, w.Body.String())
+	}
+}
+
+func TestSSEDisconnect(t *testing.T) {
+	var disconnected = make(chan struct{})
+	var root = NewRootRouter()
+	root.AddChild(NewFuncRouter("events", func(context *Context) GetResult {
+		return context.EventStream(func(s *SSEStream, context *Context) {
+			s.SendData("hello")
+			select {
+			case <-s.Done():
+				close(disconnected)
+			case <-time.After(time.Second):
+			}
+		})
+	}))
+	var processor = newTestProcessor(t, root)
+	var server = httptest.NewServer(testHandler(processor))
+	defer server.Close()
+	var ctx, cancel = context.WithCancel(context.Background())
+	var req, _ = http.NewRequestWithContext(ctx, "GET", server.URL+"/events", nil)
+	var resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var line, _ = bufio.NewReader(resp.Body).ReadString('\n')
+	if line != "data: hello\n" {
+		t.Errorf("unexpected first line %q", line)
+	}
+	cancel()
+	resp.Body.Close()
+	select {
+	case <-disconnected:
+	case <-time.After(time.Second):
+		t.Error("Done was not closed after the client disconnected")
+	}
+}
@@ -22,6 +22,11 @@ func NewStaticExecutor(path string) *StaticExecutor {
 	return se
 }
 
+// Describe 返回执行器类型(static)和文件目录路径
+func (this *StaticExecutor) Describe() (string, string) {
+	return "static", this.path
+}
+
 // Excute 执行
 func (this *StaticExecutor) Execute() (interface{}, error) {
 	var context, ok = this.Context.(*Context)
@@ -29,7 +34,8 @@ func (this *StaticExecutor) Execute() (interface{}, error) {
 		context.End = this.End
 		return this.FilterExecute(func() (interface{}, error) {
 			var result Result = nil
-			if context.HttpContext.Request.Method == "GET" {
+			var method = context.HttpContext.Request.Method
+			if method == "GET" || method == "HEAD" {
 				//返回文件
 				var pathSegs = context.AllSegments()
 				var containDotDot = false
@@ -74,6 +80,11 @@ func NewFileExecutor(path string) *FileExecutor {
 	return fe
 }
 
+// Describe 返回执行器类型(file)和文件路径
+func (this *FileExecutor) Describe() (string, string) {
+	return "file", this.path
+}
+
 // Excute 执行
 func (this *FileExecutor) Execute() (interface{}, error) {
 	var context, ok = this.Context.(*Context)
@@ -5,16 +5,27 @@ type StatusCode int
 
 const (
 	//Http状态码
-	StatusCodeOK               StatusCode = 200 //http正常返回结果
-	StatusCodeMovedPermanently StatusCode = 301 //http永久转移
-	StatusCodeMovedTemporarily StatusCode = 302 //http临时转移
-	StatusCodeNotFound         StatusCode = 404 //http页面未找到
+	StatusCodeOK                    StatusCode = 200 //http正常返回结果
+	StatusCodeNoContent             StatusCode = 204 //http无内容
+	StatusCodeMovedPermanently      StatusCode = 301 //http永久转移
+	StatusCodeMovedTemporarily      StatusCode = 302 //http临时转移
+	StatusCodeBadRequest            StatusCode = 400 //http请求错误
+	StatusCodeUnauthorized          StatusCode = 401 //http未认证
+	StatusCodeForbidden             StatusCode = 403 //http禁止访问
+	StatusCodeNotFound              StatusCode = 404 //http页面未找到
+	StatusCodeMethodNotAllowed      StatusCode = 405 //http方法不允许
+	StatusCodeRequestEntityTooLarge StatusCode = 413 //http请求体过大
+	StatusCodeUnprocessableEntity   StatusCode = 422 //http请求参数无法通过校验
+	StatusCodeInternalError         StatusCode = 500 //http服务器内部错误
+)
 
+const (
 	//框架内部状态码(功能)
-	StatusCodeRedispatch StatusCode = iota + 10000 //路由重新分发状态,接收该状态后需要将当前请求重新分发
+	//框架内部状态码的值会被外部保存和比较,新增状态码时不能改变已有状态码的值
+	StatusCodeRedispatch StatusCode = 10004 //路由重新分发状态,接收该状态后需要将当前请求重新分发
 	//框架内部状态码(错误)
-	StatusCodeParamNotCorrect //http参数不正确
-	StatusCodePageNotFound    //路由未找到
+	StatusCodeParamNotCorrect StatusCode = 10005 //http参数不正确
+	StatusCodePageNotFound    StatusCode = 10006 //路由未找到
 
 	//用户自定义状态码
 	StatusCodeUserDefined StatusCode = 1000000 //用户自定义状态码起始码
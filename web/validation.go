@@ -0,0 +1,62 @@
+package web
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kdada/tinygo/meta"
+	"github.com/kdada/tinygo/validator"
+)
+
+// 校验错误报告,包含所有未通过校验的字段
+type ValidationReport struct {
+	XMLName xml.Name              `json:"-" xml:"validation"`
+	Status  int                   `json:"status" xml:"status"`   //Http状态码
+	Message string                `json:"message" xml:"message"` //状态码对应的描述
+	Errors  meta.ValidationErrors `json:"errors" xml:"error"`    //字段校验错误
+}
+
+// NewValidationReport 创建校验错误报告
+func NewValidationReport(status StatusCode, errs meta.ValidationErrors) *ValidationReport {
+	return &ValidationReport{Status: int(status), Message: http.StatusText(int(status)), Errors: errs}
+}
+
+// Locale 返回请求的语言,使用Accept-Language中权重最高的语言,不存在时返回validator.DefaultLocale
+func (this *Context) Locale() string {
+	var locale = ""
+	var best = 0.0
+	for _, part := range strings.Split(this.HttpContext.Request.Header.Get("Accept-Language"), ",") {
+		var fields = strings.Split(part, ";")
+		var tag = strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		var q = 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				var v, err = strconv.ParseFloat(f[2:], 64)
+				if err == nil {
+					q = v
+				}
+			}
+		}
+		if q > best {
+			locale = tag
+			best = q
+		}
+	}
+	if locale == "" {
+		return validator.DefaultLocale
+	}
+	return locale
+}
+
+// ValidationReport 返回校验错误报告结果,状态码为422,使用与Api()相同的方式返回json或xml
+//  错误信息使用context.Locale()的语言生成
+func (this *Context) ValidationReport(errs meta.ValidationErrors) HttpResult {
+	errs.Localize(this.Locale())
+	return this.ApiStatus(StatusCodeUnprocessableEntity, NewValidationReport(StatusCodeUnprocessableEntity, errs))
+}
@@ -0,0 +1,51 @@
+package web
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newViewProcessor 创建视图目录为dir的处理器,别名为target的路由指向/name
+func newViewProcessor(t *testing.T, dir string, name string) *HttpProcessor {
+	var root = NewRootRouter()
+	root.AddChild(NewFuncRouter(name, func(context *Context) GetResult {
+		return context.Data([]byte(name))
+	}).SetAlias("target"))
+	root.AddChild(NewFuncRouter("page", func(context *Context) GetResult {
+		return context.View("index.html")
+	}))
+	return newTestProcessor(t, root, func(config *HttpConfig) {
+		config.TemplateConfig.SetBasePath(dir)
+		config.TemplateConfig.SetTemplateExt(".html")
+	})
+}
+
+func TestURLForPerProcessor(t *testing.T) {
+	//视图目录是相对于工作目录的路径
+	var wd, err = os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := filepath.Rel(wd, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte(`{{urlfor "target"}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//后创建的处理器不能影响先创建的处理器模板中的urlfor
+	var first = newViewProcessor(t, dir, "first")
+	var second = newViewProcessor(t, dir, "second")
+	for name, processor := range map[string]*HttpProcessor{"first": first, "second": second} {
+		var w = httptest.NewRecorder()
+		testHandler(processor).ServeHTTP(w, httptest.NewRequest("GET", "/page", nil))
+		if w.Code != 200 || strings.TrimSpace(w.Body.String()) != "/"+name {
+			t.Errorf("%s: unexpected response %d %q", name, w.Code, w.Body.String())
+		}
+	}
+}
@@ -2,7 +2,10 @@
 package web
 
 import (
+	"context"
+	"io"
 	"strconv"
+	"sync"
 
 	"github.com/kdada/tinygo/connector"
 	"github.com/kdada/tinygo/router"
@@ -10,8 +13,13 @@ import (
 
 // Web应用
 type WebApp struct {
-	Conn      connector.Connector //链接
-	Processor *HttpProcessor      //处理器
+	Conn      connector.Connector   //链接
+	Conns     []connector.Connector //额外的连接器,与Conn共享同一个处理器
+	Processor *HttpProcessor        //处理器
+	stopOnce  sync.Once             //保证连接器只停止一次
+	stopErr   error                 //停止连接器时的错误
+	mu        sync.Mutex            //保护stopped
+	stopped   bool                  //是否已经调用Stop
 }
 
 // NewWebApp 创建WebApp
@@ -25,11 +33,7 @@ func NewWebApp(appDir string, configFile string, root router.Router) (*WebApp, e
 		return nil, err
 	}
 	var conn connector.Connector
-	if config.Https {
-		conn, err = connector.NewConnector("https", ":"+strconv.Itoa(config.Port)+";Cert="+config.Cert+";Key="+config.PrivateKey)
-	} else {
-		conn, err = connector.NewConnector("http", ":"+strconv.Itoa(config.Port))
-	}
+	conn, err = NewConnector(config)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +42,118 @@ func NewWebApp(appDir string, configFile string, root router.Router) (*WebApp, e
 		return nil, e
 	}
 	conn.SetDispatcher(processor)
-	return &WebApp{conn, processor}, nil
+	var reloadable, ok = conn.(connector.ReloadableConnector)
+	if ok && processor.Logger != nil {
+		//证书重新加载失败时记录日志
+		reloadable.SetReloadHandler(func(err error) {
+			processor.Logger.Error(err)
+		})
+	}
+	var app = &WebApp{Conn: conn, Processor: processor}
+	if config.Https && config.HttpPort > 0 {
+		//同时监听http
+		conn, err = connector.NewConnector("http", ":"+strconv.Itoa(config.HttpPort)+connectorOptions(config))
+		if err != nil {
+			return nil, err
+		}
+		if config.HttpsRedirect {
+			conn.SetDispatcher(NewHttpsRedirector(config.Port))
+		} else {
+			conn.SetDispatcher(processor)
+		}
+		app.AddConnector(conn)
+	}
+	return app, nil
+}
+
+// AddConnector 添加连接器,连接器没有调度器时使用当前处理器,需要在Run之前添加
+//  Conn为空时作为Conn,否则添加到Conns
+func (this *WebApp) AddConnector(conn connector.Connector) {
+	if conn.Dispatcher() == nil {
+		conn.SetDispatcher(this.Processor)
+	}
+	if this.Conn == nil {
+		this.Conn = conn
+		return
+	}
+	this.Conns = append(this.Conns, conn)
+}
+
+// connectors 返回Conn和Conns中的所有连接器
+func (this *WebApp) connectors() []connector.Connector {
+	if this.Conn == nil {
+		return this.Conns
+	}
+	return append([]connector.Connector{this.Conn}, this.Conns...)
+}
+
+// AddHost 添加虚拟主机,参考HttpProcessor.AddHost
+func (this *WebApp) AddHost(pattern string, root router.Router) error {
+	return this.Processor.AddHost(pattern, root)
+}
+
+// Routes 返回应用中所有可以访问的路由
+func (this *WebApp) Routes() []*RouteInfo {
+	return this.Processor.Routes()
+}
+
+// PrintRoutes 将应用中所有可以访问的路由写入writer
+//  format:输出格式,json为json数组,其他为文本表格
+func (this *WebApp) PrintRoutes(writer io.Writer, format string) error {
+	return WriteRoutes(writer, this.Routes(), format)
+}
+
+// NewConnector 根据配置中的Listen和Https创建连接器,Listen不是tcp,unix或fd时返回错误
+func NewConnector(config *HttpConfig) (connector.Connector, error) {
+	var options = connectorOptions(config)
+	if config.Https {
+		options = tlsOptions(config) + options
+	}
+	switch config.Listen {
+	case "unix":
+		return connector.NewConnector("unix", config.UnixSocket+";Mode="+config.UnixSocketMode+options)
+	case "fd":
+		return connector.NewConnector("fd", config.ListenFd+options)
+	case "tcp", "":
+	default:
+		return nil, ErrorInvalidListen.Format(config.Listen).Error()
+	}
+	if config.Https {
+		return connector.NewConnector("https", ":"+strconv.Itoa(config.Port)+options)
+	}
+	return connector.NewConnector("http", ":"+strconv.Itoa(config.Port)+options)
+}
+
+// connectorOptions 根据配置生成连接器source中的可选参数
+func connectorOptions(config *HttpConfig) string {
+	return ";DrainTimeout=" + strconv.Itoa(config.DrainTimeout) +
+		";ReadTimeout=" + strconv.Itoa(config.ReadTimeout) +
+		";ReadHeaderTimeout=" + strconv.Itoa(config.ReadHeaderTimeout) +
+		";WriteTimeout=" + strconv.Itoa(config.WriteTimeout) +
+		";IdleTimeout=" + strconv.Itoa(config.IdleTimeout) +
+		";MaxHeaderBytes=" + strconv.Itoa(config.MaxHeaderBytes) +
+		";ProxyProtocol=" + strconv.FormatBool(config.ProxyProtocol)
+}
+
+// tlsOptions 根据配置生成连接器source中的tls参数
+func tlsOptions(config *HttpConfig) string {
+	var options = ";Cert=" + config.Cert + ";Key=" + config.PrivateKey
+	var params = []struct {
+		name  string
+		value string
+	}{
+		{"SNI", config.SNI},
+		{"ClientCA", config.ClientCA},
+		{"ClientAuth", config.ClientAuth},
+		{"MinVersion", config.TLSMinVersion},
+		{"CipherSuites", config.TLSCipherSuites},
+	}
+	for _, p := range params {
+		if p.value != "" {
+			options += ";" + p.name + "=" + p.value
+		}
+	}
+	return options
 }
 
 // Name 应用名称
@@ -48,10 +163,98 @@ func (this *WebApp) Name() string {
 
 // Init 应用初始化接口
 func (this *WebApp) Init() error {
-	return this.Conn.Init()
+	for _, conn := range this.connectors() {
+		var err = conn.Init()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// Run 应用运行接口
+// Run 应用运行接口,同时运行所有连接器,任意一个连接器出错时停止其他连接器并返回该错误
+//  出错时连接器会被重置,因此可以按照重启策略再次运行,调用Stop之后Run直接返回nil
 func (this *WebApp) Run() error {
-	return this.Conn.Run()
+	this.mu.Lock()
+	var stopped = this.stopped
+	this.mu.Unlock()
+	if stopped {
+		return nil
+	}
+	type runResult struct {
+		conn connector.Connector
+		err  error
+	}
+	var conns = this.connectors()
+	var result = make(chan runResult, len(conns))
+	for _, conn := range conns {
+		go func(conn connector.Connector) {
+			result <- runResult{conn, conn.Run()}
+		}(conn)
+	}
+	var err error
+	for range conns {
+		var r = <-result
+		if r.err != nil && err == nil {
+			err = r.err
+			//只停止其他连接器,出错的连接器已经结束运行
+			stopConnectors(conns, r.conn)
+		}
+	}
+	if err != nil {
+		for _, conn := range conns {
+			var rc, ok = conn.(connector.ResettableConnector)
+			if ok {
+				rc.Reset()
+			}
+		}
+	}
+	return err
+}
+
+// stopConnectors 同时停止conns中除了skip之外的连接器,返回第一个错误
+func stopConnectors(conns []connector.Connector, skip connector.Connector) error {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var result error
+	for _, conn := range conns {
+		if conn == skip {
+			continue
+		}
+		wg.Add(1)
+		go func(conn connector.Connector) {
+			defer wg.Done()
+			var err = conn.Stop()
+			mu.Lock()
+			if err != nil && result == nil {
+				result = err
+			}
+			mu.Unlock()
+		}(conn)
+	}
+	wg.Wait()
+	return result
+}
+
+// Stop 停止连接器,关闭WebSocket连接并释放处理器持有的资源,未能在ctx结束前完成时返回ctx的错误
+//  处理器持有的资源(例如日志)在连接器停止后才会释放,ctx结束时仍在处理的请求可以继续使用
+func (this *WebApp) Stop(ctx context.Context) error {
+	this.mu.Lock()
+	this.stopped = true
+	this.mu.Unlock()
+	var result = make(chan error, 1)
+	go func() {
+		this.stopOnce.Do(func() {
+			this.stopErr = stopConnectors(this.connectors(), nil)
+			this.Processor.CloseWebSockets()
+			this.Processor.Close()
+		})
+		result <- this.stopErr
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
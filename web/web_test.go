@@ -0,0 +1,59 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kdada/tinygo/connector"
+	"github.com/kdada/tinygo/router"
+)
+
+// newTestProcessor 创建测试使用的处理器,日志输出到控制台,不使用session
+func newTestProcessor(t *testing.T, root router.Router, configure ...func(config *HttpConfig)) *HttpProcessor {
+	var config = NewHttpConfig()
+	config.Log = true
+	config.LogType = "console"
+	config.Session = false
+	for _, f := range configure {
+		f(config)
+	}
+	var processor, err = NewHttpProcessor(root, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(processor.Close)
+	return processor
+}
+
+// testHandler 返回将请求分发给processor的http.Handler
+func testHandler(processor *HttpProcessor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		processor.Dispatch(strings.Split(r.URL.Path, "/"), &connector.HttpContext{Request: r, ResponseWriter: w})
+	})
+}
+
+func TestWebAppConnectors(t *testing.T) {
+	var app = &WebApp{Processor: newTestProcessor(t, NewRootRouter())}
+	var conns = make([]connector.Connector, 0, 2)
+	for i := 0; i < 2; i++ {
+		var conn, err = connector.NewConnector("http", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		app.AddConnector(conn)
+		conns = append(conns, conn)
+	}
+	//第一个连接器作为Conn,其余的连接器放入Conns
+	if app.Conn != conns[0] || len(app.Conns) != 1 || app.Conns[0] != conns[1] {
+		t.Errorf("unexpected connectors %v %v", app.Conn, app.Conns)
+	}
+	if all := app.connectors(); len(all) != 2 || all[0] != conns[0] || all[1] != conns[1] {
+		t.Errorf("unexpected connector list %v", all)
+	}
+	for _, conn := range conns {
+		if conn.Dispatcher() != app.Processor {
+			t.Error("connector should dispatch to the app processor")
+		}
+	}
+}
@@ -0,0 +1,582 @@
+package web
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kdada/tinygo/router"
+)
+
+// WebSocket消息类型
+const (
+	WebSocketText   = 1  //文本消息
+	WebSocketBinary = 2  //二进制消息
+	WebSocketClose  = 8  //关闭帧
+	WebSocketPing   = 9  //ping帧
+	WebSocketPong   = 10 //pong帧
+)
+
+// WebSocket关闭码
+const (
+	CloseNormal          = 1000 //正常关闭
+	CloseGoingAway       = 1001 //服务端关闭或者浏览器离开页面
+	CloseProtocolError   = 1002 //协议错误
+	CloseUnsupportedData = 1003 //不支持的数据类型
+	CloseNoStatus        = 1005 //关闭帧中没有关闭码
+	CloseAbnormal        = 1006 //连接异常断开
+	CloseInvalidPayload  = 1007 //数据与消息类型不符(例如文本消息不是utf8编码)
+	ClosePolicyViolation = 1008 //违反策略
+	CloseMessageTooBig   = 1009 //消息过大
+	CloseInternalError   = 1011 //服务端内部错误
+)
+
+// WebSocket握手使用的GUID
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// 默认的单条消息最大字节数
+const DefaultWebSocketReadLimit = 1 << 20
+
+// 关闭连接时等待对方关闭帧的时间
+const webSocketCloseTimeout = 5 * time.Second
+
+// WebSocket连接处理方法,方法返回后连接将被关闭
+type WebSocketHandler func(conn *WebSocketConn, context *Context)
+
+// WebSocket关闭错误,收到关闭帧或者出现协议错误时返回
+type WebSocketCloseError struct {
+	Code int    //关闭码
+	Text string //关闭原因
+}
+
+// Error 返回错误描述
+func (this *WebSocketCloseError) Error() string {
+	return fmt.Sprintf("WebSocket连接已关闭(%d):%s", this.Code, this.Text)
+}
+
+// WebSocket连接
+type WebSocketConn struct {
+	conn          net.Conn          //底层连接
+	reader        *bufio.Reader     //读取器,包含握手时已缓冲的数据
+	subprotocol   string            //协商的子协议
+	readLimit     int64             //单条消息最大字节数,为0时不限制
+	reading       int32             //是否有goroutine正在读取
+	pongHandler   func(data []byte) //收到pong帧时的处理方法
+	wmu           sync.Mutex        //保证帧的写入不会交错,同时保护readErr和closeSent
+	readErr       error             //读取出错后保存错误,之后的读取都返回该错误
+	closeSent     bool              //是否已经发送关闭帧
+	closeReceived chan struct{}     //收到关闭帧后关闭
+	receivedOnce  sync.Once         //保证closeReceived只关闭一次
+	closeOnce     sync.Once         //保证底层连接只关闭一次
+}
+
+// newWebSocketConn 创建WebSocket连接
+func newWebSocketConn(conn net.Conn, reader *bufio.Reader, subprotocol string) *WebSocketConn {
+	return &WebSocketConn{
+		conn:          conn,
+		reader:        reader,
+		subprotocol:   subprotocol,
+		readLimit:     DefaultWebSocketReadLimit,
+		closeReceived: make(chan struct{}),
+	}
+}
+
+// Subprotocol 返回协商的子协议,没有协商子协议时返回空字符串
+func (this *WebSocketConn) Subprotocol() string {
+	return this.subprotocol
+}
+
+// RemoteAddr 返回远程地址
+func (this *WebSocketConn) RemoteAddr() net.Addr {
+	return this.conn.RemoteAddr()
+}
+
+// SetReadLimit 设置单条消息的最大字节数,超过时以CloseMessageTooBig关闭连接,为0时不限制
+func (this *WebSocketConn) SetReadLimit(limit int64) {
+	this.readLimit = limit
+}
+
+// SetReadDeadline 设置读取超时时间
+func (this *WebSocketConn) SetReadDeadline(t time.Time) error {
+	return this.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline 设置写入超时时间
+func (this *WebSocketConn) SetWriteDeadline(t time.Time) error {
+	return this.conn.SetWriteDeadline(t)
+}
+
+// SetPongHandler 设置收到pong帧时的处理方法,可以用于检测连接是否存活
+func (this *WebSocketConn) SetPongHandler(handler func(data []byte)) {
+	this.pongHandler = handler
+}
+
+// ReadMessage 读取一条完整的消息,自动回复ping帧并处理关闭握手
+//  同一时间只能有一个goroutine读取
+//  return:(消息类型,消息内容,错误),收到关闭帧时返回*WebSocketCloseError
+func (this *WebSocketConn) ReadMessage() (int, []byte, error) {
+	atomic.StoreInt32(&this.reading, 1)
+	defer atomic.StoreInt32(&this.reading, 0)
+	var readErr = this.readError()
+	if readErr != nil {
+		return 0, nil, readErr
+	}
+	var messageType = 0
+	var message []byte
+	for {
+		var fin, opcode, payload, err = this.readFrame(int64(len(message)))
+		if err != nil {
+			return 0, nil, this.fail(err)
+		}
+		switch opcode {
+		case WebSocketPing:
+			err = this.writeFrame(WebSocketPong, payload)
+			if err != nil && err != errWebSocketCloseSent {
+				return 0, nil, this.fail(err)
+			}
+			continue
+		case WebSocketPong:
+			if this.pongHandler != nil {
+				this.pongHandler(payload)
+			}
+			continue
+		case WebSocketClose:
+			return 0, nil, this.fail(this.receiveClose(payload))
+		case WebSocketText, WebSocketBinary:
+			if messageType != 0 {
+				return 0, nil, this.fail(&WebSocketCloseError{CloseProtocolError, "分片消息未结束"})
+			}
+			messageType = int(opcode)
+		case 0:
+			if messageType == 0 {
+				return 0, nil, this.fail(&WebSocketCloseError{CloseProtocolError, "无效的延续帧"})
+			}
+		default:
+			return 0, nil, this.fail(&WebSocketCloseError{CloseProtocolError, "无效的操作码"})
+		}
+		message = append(message, payload...)
+		if fin {
+			if messageType == WebSocketText && !utf8.Valid(message) {
+				return 0, nil, this.fail(&WebSocketCloseError{CloseInvalidPayload, "文本消息不是有效的utf8编码"})
+			}
+			return messageType, message, nil
+		}
+	}
+}
+
+// readFrame 读取一个帧,客户端发送的帧必须带有掩码
+//  size:当前消息已经读取的字节数
+func (this *WebSocketConn) readFrame(size int64) (bool, byte, []byte, error) {
+	var header [8]byte
+	var _, err = io.ReadFull(this.reader, header[:2])
+	if err != nil {
+		return false, 0, nil, err
+	}
+	var fin = header[0]&0x80 != 0
+	var opcode = header[0] & 0x0F
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, &WebSocketCloseError{CloseProtocolError, "不支持的扩展"}
+	}
+	if header[1]&0x80 == 0 {
+		return false, 0, nil, &WebSocketCloseError{CloseProtocolError, "客户端帧没有掩码"}
+	}
+	var length = uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		_, err = io.ReadFull(this.reader, header[:2])
+		length = uint64(binary.BigEndian.Uint16(header[:2]))
+	case 127:
+		_, err = io.ReadFull(this.reader, header[:8])
+		length = binary.BigEndian.Uint64(header[:8])
+	}
+	if err != nil {
+		return false, 0, nil, err
+	}
+	if opcode >= WebSocketClose && (!fin || length > 125) {
+		return false, 0, nil, &WebSocketCloseError{CloseProtocolError, "无效的控制帧"}
+	}
+	if length > 1<<62 || (this.readLimit > 0 && opcode < WebSocketClose && size+int64(length) > this.readLimit) {
+		return false, 0, nil, &WebSocketCloseError{CloseMessageTooBig, "消息过大"}
+	}
+	var mask [4]byte
+	_, err = io.ReadFull(this.reader, mask[:])
+	if err != nil {
+		return false, 0, nil, err
+	}
+	var payload = make([]byte, length)
+	_, err = io.ReadFull(this.reader, payload)
+	if err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// receiveClose 处理关闭帧,回复关闭帧并关闭底层连接
+func (this *WebSocketConn) receiveClose(payload []byte) error {
+	var closeErr = &WebSocketCloseError{CloseNoStatus, ""}
+	if len(payload) == 1 {
+		closeErr = &WebSocketCloseError{CloseProtocolError, "无效的关闭帧"}
+	} else if len(payload) >= 2 {
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Text = string(payload[2:])
+		if !validCloseCode(closeErr.Code) || !utf8.Valid(payload[2:]) {
+			closeErr = &WebSocketCloseError{CloseProtocolError, "无效的关闭帧"}
+		}
+	}
+	this.receivedOnce.Do(func() {
+		close(this.closeReceived)
+	})
+	return closeErr
+}
+
+// validCloseCode 判断关闭帧中的关闭码是否有效
+func validCloseCode(code int) bool {
+	switch {
+	case code >= 1000 && code <= 1003, code >= 1007 && code <= 1011, code >= 3000 && code <= 4999:
+		return true
+	}
+	return false
+}
+
+// fail 读取出错时关闭连接,协议错误时先发送相应的关闭帧
+func (this *WebSocketConn) fail(err error) error {
+	var closeErr, ok = err.(*WebSocketCloseError)
+	if ok {
+		var code = closeErr.Code
+		var text = ""
+		if code == CloseNoStatus {
+			code = CloseNormal
+		} else if code != CloseNormal {
+			text = closeErr.Text
+		}
+		this.writeClose(code, text)
+	} else if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = &WebSocketCloseError{CloseAbnormal, err.Error()}
+	}
+	this.wmu.Lock()
+	this.readErr = err
+	this.wmu.Unlock()
+	this.closeConn()
+	return err
+}
+
+// readError 返回读取时保存的错误
+func (this *WebSocketConn) readError() error {
+	this.wmu.Lock()
+	defer this.wmu.Unlock()
+	return this.readErr
+}
+
+// WriteMessage 写入一条消息
+//  messageType:WebSocketText或者WebSocketBinary
+func (this *WebSocketConn) WriteMessage(messageType int, data []byte) error {
+	if messageType != WebSocketText && messageType != WebSocketBinary {
+		return ErrorInvalidWebSocketMessage.Format(messageType).Error()
+	}
+	return this.writeFrame(byte(messageType), data)
+}
+
+// WriteText 写入一条文本消息
+func (this *WebSocketConn) WriteText(text string) error {
+	return this.writeFrame(WebSocketText, []byte(text))
+}
+
+// Ping 发送ping帧,客户端会回复相同内容的pong帧
+func (this *WebSocketConn) Ping(data []byte) error {
+	if len(data) > 125 {
+		return ErrorInvalidWebSocketMessage.Format(WebSocketPing).Error()
+	}
+	return this.writeFrame(WebSocketPing, data)
+}
+
+// 已经发送关闭帧
+var errWebSocketCloseSent = ErrorWebSocketClosed.Error()
+
+// writeFrame 写入一个不分片的帧,服务端帧不带掩码
+func (this *WebSocketConn) writeFrame(opcode byte, data []byte) error {
+	this.wmu.Lock()
+	defer this.wmu.Unlock()
+	if this.closeSent {
+		return errWebSocketCloseSent
+	}
+	if opcode == WebSocketClose {
+		this.closeSent = true
+	}
+	var frame = make([]byte, 0, len(data)+10)
+	frame = append(frame, 0x80|opcode)
+	switch {
+	case len(data) <= 125:
+		frame = append(frame, byte(len(data)))
+	case len(data) <= 0xFFFF:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(data)))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(data)))
+	}
+	frame = append(frame, data...)
+	var _, err = this.conn.Write(frame)
+	return err
+}
+
+// writeClose 发送关闭帧
+func (this *WebSocketConn) writeClose(code int, text string) error {
+	var payload = binary.BigEndian.AppendUint16(nil, uint16(code))
+	if len(text) > 123 {
+		text = text[:123]
+	}
+	payload = append(payload, text...)
+	this.conn.SetWriteDeadline(time.Now().Add(webSocketCloseTimeout))
+	return this.writeFrame(WebSocketClose, payload)
+}
+
+// Close 使用CloseNormal关闭连接
+func (this *WebSocketConn) Close() error {
+	return this.CloseWithCode(CloseNormal, "")
+}
+
+// CloseWithCode 发送关闭帧,等待对方的关闭帧后关闭连接
+func (this *WebSocketConn) CloseWithCode(code int, text string) error {
+	var err = this.writeClose(code, text)
+	if err == nil {
+		if atomic.LoadInt32(&this.reading) == 1 {
+			//其他goroutine正在读取,等待其收到关闭帧
+			select {
+			case <-this.closeReceived:
+			case <-time.After(webSocketCloseTimeout):
+			}
+		} else if this.readError() == nil {
+			this.conn.SetReadDeadline(time.Now().Add(webSocketCloseTimeout))
+			for {
+				var _, _, e = this.ReadMessage()
+				if e != nil {
+					break
+				}
+			}
+		}
+	} else if err == errWebSocketCloseSent {
+		err = nil
+	}
+	this.closeConn()
+	return err
+}
+
+// closeConn 关闭底层连接
+func (this *WebSocketConn) closeConn() {
+	this.closeOnce.Do(func() {
+		this.conn.Close()
+	})
+}
+
+// trackWebSocket 记录正在处理的WebSocket连接,处理完成后需要调用返回的方法
+//  return:(处理完成时调用的方法,是否可以处理连接),已经调用CloseWebSockets时不能再处理连接
+func (this *HttpProcessor) trackWebSocket(ws *WebSocketConn) (func(), bool) {
+	this.wsMu.Lock()
+	defer this.wsMu.Unlock()
+	if this.wsClosed {
+		return nil, false
+	}
+	if this.webSockets == nil {
+		this.webSockets = make(map[*WebSocketConn]bool)
+	}
+	this.webSockets[ws] = true
+	this.wsWait.Add(1)
+	return func() {
+		this.wsMu.Lock()
+		delete(this.webSockets, ws)
+		this.wsMu.Unlock()
+		this.wsWait.Done()
+	}, true
+}
+
+// CloseWebSockets 使用CloseGoingAway关闭全部WebSocket连接并等待处理方法返回
+//  http服务停止时不会等待已经完成握手的连接,需要在连接器停止后调用,之后完成握手的连接会被立即关闭
+func (this *HttpProcessor) CloseWebSockets() {
+	this.wsMu.Lock()
+	this.wsClosed = true
+	var conns = make([]*WebSocketConn, 0, len(this.webSockets))
+	for ws := range this.webSockets {
+		conns = append(conns, ws)
+	}
+	this.wsMu.Unlock()
+	var wg sync.WaitGroup
+	for _, ws := range conns {
+		wg.Add(1)
+		go func(ws *WebSocketConn) {
+			defer wg.Done()
+			ws.CloseWithCode(CloseGoingAway, "")
+		}(ws)
+	}
+	wg.Wait()
+	this.wsWait.Wait()
+}
+
+// WebSocket结果,写入时完成握手并使用Handler处理连接
+type WebSocketResult struct {
+	CommonHttpResult
+	Context      *Context                         //请求上下文
+	Handler      WebSocketHandler                 //连接处理方法
+	Subprotocols []string                         //服务端支持的子协议,按优先级排列
+	CheckOrigin  func(request *http.Request) bool //检查Origin,为nil时只允许没有Origin或者Origin与Host相同的请求
+}
+
+// WriteTo 完成握手并处理连接,握手失败时返回400
+func (this *WebSocketResult) WriteTo(writer io.Writer) error {
+	var w, ok = writer.(http.ResponseWriter)
+	if !ok {
+		return ErrorInvalidWriter.Error()
+	}
+	var r = this.Context.HttpContext.Request
+	var key, err = this.handshake(r)
+	if err != nil {
+		if r.Header.Get("Sec-WebSocket-Version") != "13" {
+			w.Header().Set("Sec-WebSocket-Version", "13")
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil
+	}
+	var hijacker, hijackable = w.(http.Hijacker)
+	if !hijackable {
+		return ErrorWebSocketHandshake.Format("连接不支持Hijack").Error()
+	}
+	var subprotocol = this.subprotocol(r)
+	conn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return err
+	}
+	//清除http服务设置的超时时间
+	conn.SetDeadline(time.Time{})
+	var hash = sha1.Sum([]byte(key + webSocketGUID))
+	var response = "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(hash[:]) + "\r\n"
+	if subprotocol != "" {
+		response += "Sec-WebSocket-Protocol: " + subprotocol + "\r\n"
+	}
+	//保留已经设置的响应头,例如session的Set-Cookie
+	for name, values := range w.Header() {
+		for _, v := range values {
+			response += name + ": " + v + "\r\n"
+		}
+	}
+	_, err = conn.Write([]byte(response + "\r\n"))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	var ws = newWebSocketConn(conn, brw.Reader, subprotocol)
+	var done, tracked = this.Context.Processor.trackWebSocket(ws)
+	if !tracked {
+		//处理器正在停止
+		return ws.CloseWithCode(CloseGoingAway, "")
+	}
+	defer done()
+	defer ws.Close()
+	this.Handler(ws, this.Context)
+	return nil
+}
+
+// handshake 检查握手请求,返回Sec-WebSocket-Key
+func (this *WebSocketResult) handshake(r *http.Request) (string, error) {
+	if r.Method != "GET" {
+		return "", ErrorWebSocketHandshake.Format("请求方法必须是GET").Error()
+	}
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		return "", ErrorWebSocketHandshake.Format("不是WebSocket请求").Error()
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return "", ErrorWebSocketHandshake.Format("不支持的版本").Error()
+	}
+	var key = strings.TrimSpace(r.Header.Get("Sec-WebSocket-Key"))
+	var data, err = base64.StdEncoding.DecodeString(key)
+	if err != nil || len(data) != 16 {
+		return "", ErrorWebSocketHandshake.Format("无效的Sec-WebSocket-Key").Error()
+	}
+	var check = this.CheckOrigin
+	if check == nil {
+		check = sameOrigin
+	}
+	if !check(r) {
+		return "", ErrorWebSocketHandshake.Format("不允许的Origin").Error()
+	}
+	return key, nil
+}
+
+// subprotocol 选择客户端和服务端都支持的子协议
+func (this *WebSocketResult) subprotocol(r *http.Request) string {
+	for _, p := range this.Subprotocols {
+		if headerContains(r.Header, "Sec-WebSocket-Protocol", p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// headerContains 判断以逗号分隔的请求头中是否包含token(不区分大小写)
+func headerContains(header http.Header, name string, token string) bool {
+	for _, value := range header[name] {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameOrigin 判断请求没有Origin或者Origin的主机与Host相同
+func sameOrigin(r *http.Request) bool {
+	var origin = r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	var u, err = url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// WebSocket执行器,前置过滤器执行后进行WebSocket握手
+type WebSocketExecutor struct {
+	router.BaseRouterExecutor
+	handler WebSocketHandler
+}
+
+// NewWebSocketExecutor 创建WebSocket执行器
+func NewWebSocketExecutor(handler WebSocketHandler) *WebSocketExecutor {
+	var we = new(WebSocketExecutor)
+	we.handler = handler
+	return we
+}
+
+// Describe 返回执行器类型(websocket)和连接处理方法名称
+func (this *WebSocketExecutor) Describe() (string, string) {
+	return "websocket", funcName(reflect.ValueOf(this.handler))
+}
+
+// Excute 执行
+func (this *WebSocketExecutor) Execute() (interface{}, error) {
+	var context, ok = this.Context.(*Context)
+	if ok {
+		context.End = this.End
+		return this.FilterExecute(func() (interface{}, error) {
+			return context.WebSocket(this.handler), nil
+		})
+	}
+	return nil, ErrorInvalidContext.Format(reflect.TypeOf(this.Context).String()).Error()
+}
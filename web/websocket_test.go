@@ -0,0 +1,387 @@
+package web
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTestFrame 以客户端身份写入一个帧,masked为false时不带掩码
+func writeTestFrame(t *testing.T, w io.Writer, fin bool, opcode byte, payload []byte, masked bool) {
+	var frame = []byte{opcode, 0}
+	if fin {
+		frame[0] |= 0x80
+	}
+	if masked {
+		frame[1] = 0x80
+	}
+	switch {
+	case len(payload) <= 125:
+		frame[1] |= byte(len(payload))
+	case len(payload) <= 0xFFFF:
+		frame[1] |= 126
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	default:
+		frame[1] |= 127
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(payload)))
+	}
+	var data = append([]byte(nil), payload...)
+	if masked {
+		var mask = []byte{0x12, 0x34, 0x56, 0x78}
+		frame = append(frame, mask...)
+		for i := range data {
+			data[i] ^= mask[i%4]
+		}
+	}
+	var _, err = w.Write(append(frame, data...))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// readTestFrame 以客户端身份读取一个服务端帧
+func readTestFrame(t *testing.T, r io.Reader) (bool, byte, []byte) {
+	var header = make([]byte, 2)
+	var _, err = io.ReadFull(r, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header[1]&0x80 != 0 {
+		t.Error("server frame must not be masked")
+	}
+	var length = uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		io.ReadFull(r, header)
+		length = uint64(binary.BigEndian.Uint16(header))
+	case 127:
+		var ext = make([]byte, 8)
+		io.ReadFull(r, ext)
+		length = binary.BigEndian.Uint64(ext)
+	}
+	var payload = make([]byte, length)
+	_, err = io.ReadFull(r, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return header[0]&0x80 != 0, header[0] & 0x0F, payload
+}
+
+// closePayload 生成关闭帧内容
+func closePayload(code int, text string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(code)), text...)
+}
+
+// expectClose 读取关闭帧并检查关闭码
+func expectClose(t *testing.T, r io.Reader, code int) {
+	var _, opcode, payload = readTestFrame(t, r)
+	if opcode != WebSocketClose || len(payload) < 2 || int(binary.BigEndian.Uint16(payload)) != code {
+		t.Errorf("expected close frame %d, got opcode %d payload %v", code, opcode, payload)
+	}
+}
+
+// newTestWebSocket 使用net.Pipe创建服务端WebSocket连接和客户端连接
+func newTestWebSocket() (*WebSocketConn, net.Conn) {
+	var server, client = net.Pipe()
+	return newWebSocketConn(server, bufio.NewReader(server), ""), client
+}
+
+type readResult struct {
+	messageType int
+	message     []byte
+	err         error
+}
+
+// readAsync 在其他goroutine中读取一条消息
+func readAsync(ws *WebSocketConn) <-chan readResult {
+	var result = make(chan readResult, 1)
+	go func() {
+		var messageType, message, err = ws.ReadMessage()
+		result <- readResult{messageType, message, err}
+	}()
+	return result
+}
+
+func TestWebSocketFrames(t *testing.T) {
+	var ws, client = newTestWebSocket()
+	defer client.Close()
+	//掩码和16位长度
+	var payload = []byte(strings.Repeat("0123456789", 30))
+	go writeTestFrame(t, client, true, WebSocketBinary, payload, true)
+	var messageType, message, err = ws.ReadMessage()
+	if err != nil || messageType != WebSocketBinary || string(message) != string(payload) {
+		t.Errorf("unexpected message (%d, %q, %v)", messageType, message, err)
+	}
+	//服务端帧
+	go ws.WriteText("hello")
+	var fin, opcode, data = readTestFrame(t, client)
+	if !fin || opcode != WebSocketText || string(data) != "hello" {
+		t.Errorf("unexpected server frame (%v, %d, %q)", fin, opcode, data)
+	}
+	//客户端帧必须带掩码
+	go writeTestFrame(t, client, true, WebSocketText, []byte("plain"), false)
+	var result = readAsync(ws)
+	expectClose(t, client, CloseProtocolError)
+	var r = <-result
+	var closeErr, ok = r.err.(*WebSocketCloseError)
+	if !ok || closeErr.Code != CloseProtocolError {
+		t.Errorf("expected protocol error, got %v", r.err)
+	}
+	//出错后的读取返回相同的错误
+	if _, _, err = ws.ReadMessage(); err != r.err {
+		t.Errorf("expected saved read error, got %v", err)
+	}
+}
+
+func TestWebSocketFragmentation(t *testing.T) {
+	var ws, client = newTestWebSocket()
+	defer client.Close()
+	var pong []byte
+	ws.SetPongHandler(func(data []byte) {
+		pong = data
+	})
+	go func() {
+		writeTestFrame(t, client, false, WebSocketText, []byte("Hel"), true)
+		writeTestFrame(t, client, true, WebSocketPing, []byte("ping"), true)
+		writeTestFrame(t, client, true, WebSocketPong, []byte("pong"), true)
+		writeTestFrame(t, client, true, 0, []byte("lo"), true)
+	}()
+	var result = readAsync(ws)
+	//分片之间的ping帧会被自动回复
+	var _, opcode, data = readTestFrame(t, client)
+	if opcode != WebSocketPong || string(data) != "ping" {
+		t.Errorf("expected pong frame, got (%d, %q)", opcode, data)
+	}
+	var r = <-result
+	if r.err != nil || r.messageType != WebSocketText || string(r.message) != "Hello" {
+		t.Errorf("unexpected message (%d, %q, %v)", r.messageType, r.message, r.err)
+	}
+	if string(pong) != "pong" {
+		t.Errorf("pong handler got %q", pong)
+	}
+	//没有开始帧的延续帧
+	go writeTestFrame(t, client, true, 0, []byte("x"), true)
+	result = readAsync(ws)
+	expectClose(t, client, CloseProtocolError)
+	if r = <-result; r.err == nil {
+		t.Error("expected error for continuation frame without start")
+	}
+}
+
+func TestWebSocketInvalidMessages(t *testing.T) {
+	var cases = []struct {
+		fin     bool
+		opcode  byte
+		payload []byte
+		code    int
+	}{
+		{true, WebSocketText, []byte{0xff, 0xfe}, CloseInvalidPayload},
+		{false, WebSocketPing, nil, CloseProtocolError},
+		{true, WebSocketPing, make([]byte, 126), CloseProtocolError},
+		{true, 3, nil, CloseProtocolError},
+		{true, WebSocketBinary, make([]byte, 20), CloseMessageTooBig},
+	}
+	for _, c := range cases {
+		var ws, client = newTestWebSocket()
+		ws.SetReadLimit(10)
+		go writeTestFrame(t, client, c.fin, c.opcode, c.payload, true)
+		var result = readAsync(ws)
+		expectClose(t, client, c.code)
+		var r = <-result
+		var closeErr, ok = r.err.(*WebSocketCloseError)
+		if !ok || closeErr.Code != c.code {
+			t.Errorf("opcode %d: expected close code %d, got %v", c.opcode, c.code, r.err)
+		}
+		client.Close()
+	}
+}
+
+func TestWebSocketCloseHandshake(t *testing.T) {
+	//服务端发起关闭
+	var ws, client = newTestWebSocket()
+	var closed = make(chan error, 1)
+	go func() {
+		closed <- ws.CloseWithCode(CloseNormal, "bye")
+	}()
+	var _, opcode, payload = readTestFrame(t, client)
+	if opcode != WebSocketClose || string(payload) != string(closePayload(CloseNormal, "bye")) {
+		t.Errorf("unexpected close frame (%d, %v)", opcode, payload)
+	}
+	writeTestFrame(t, client, true, WebSocketClose, closePayload(CloseNormal, ""), true)
+	if err := <-closed; err != nil {
+		t.Errorf("close failed: %v", err)
+	}
+	if _, err := client.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("expected closed connection, got %v", err)
+	}
+	if err := ws.WriteText("late"); err == nil {
+		t.Error("write after close should fail")
+	}
+
+	//客户端发起关闭,服务端回复相同的关闭码
+	ws, client = newTestWebSocket()
+	go writeTestFrame(t, client, true, WebSocketClose, closePayload(CloseGoingAway, "away"), true)
+	var result = readAsync(ws)
+	expectClose(t, client, CloseGoingAway)
+	var r = <-result
+	var closeErr, ok = r.err.(*WebSocketCloseError)
+	if !ok || closeErr.Code != CloseGoingAway || closeErr.Text != "away" {
+		t.Errorf("unexpected close error %v", r.err)
+	}
+	client.Close()
+}
+
+func TestWebSocketCloseWhileReading(t *testing.T) {
+	var ws, client = newTestWebSocket()
+	defer client.Close()
+	var result = readAsync(ws)
+	time.Sleep(10 * time.Millisecond)
+	var closed = make(chan error, 1)
+	go func() {
+		closed <- ws.CloseWithCode(CloseGoingAway, "")
+	}()
+	expectClose(t, client, CloseGoingAway)
+	writeTestFrame(t, client, true, WebSocketClose, closePayload(CloseGoingAway, ""), true)
+	var r = <-result
+	if closeErr, ok := r.err.(*WebSocketCloseError); !ok || closeErr.Code != CloseGoingAway {
+		t.Errorf("reader expected close error, got %v", r.err)
+	}
+	if err := <-closed; err != nil {
+		t.Errorf("close failed: %v", err)
+	}
+}
+
+// dialWebSocket 发送握手请求,返回连接和响应
+func dialWebSocket(t *testing.T, addr string, header http.Header) (net.Conn, *bufio.Reader, *http.Response) {
+	var conn, err = net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req, _ = http.NewRequest("GET", "http://"+addr+"/ws", nil)
+	req.Header = header
+	if err = req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	var reader = bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, reader, resp
+}
+
+// webSocketHeader 返回握手请求头
+func webSocketHeader() http.Header {
+	return http.Header{
+		"Connection":             {"Upgrade"},
+		"Upgrade":                {"websocket"},
+		"Sec-Websocket-Version":  {"13"},
+		"Sec-Websocket-Key":      {"dGhlIHNhbXBsZSBub25jZQ=="},
+		"Sec-Websocket-Protocol": {"chat, json"},
+	}
+}
+
+func TestWebSocketHandshake(t *testing.T) {
+	var root = NewRootRouter()
+	var handler = func(conn *WebSocketConn, context *Context) {
+		for {
+			var messageType, message, err = conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(messageType, message)
+		}
+	}
+	var ws = NewWebSocketRouter("ws", func(conn *WebSocketConn, context *Context) {
+		handler(conn, context)
+	})
+	root.AddChild(ws)
+	var processor = newTestProcessor(t, root)
+	var server = httptest.NewServer(testHandler(processor))
+	defer server.Close()
+	var addr = server.Listener.Addr().String()
+
+	var conn, reader, resp = dialWebSocket(t, addr, webSocketHeader())
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %d", resp.StatusCode)
+	}
+	//RFC 6455 1.3中的示例
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("unexpected Sec-WebSocket-Accept %q", accept)
+	}
+	writeTestFrame(t, conn, true, WebSocketText, []byte("echo"), true)
+	if _, opcode, data := readTestFrame(t, reader); opcode != WebSocketText || string(data) != "echo" {
+		t.Errorf("unexpected echo (%d, %q)", opcode, data)
+	}
+	conn.Close()
+
+	//无效的握手
+	var header = webSocketHeader()
+	header.Set("Sec-WebSocket-Key", "short")
+	header.Set("Sec-WebSocket-Version", "8")
+	conn, _, resp = dialWebSocket(t, addr, header)
+	if resp.StatusCode != http.StatusBadRequest || resp.Header.Get("Sec-WebSocket-Version") != "13" {
+		t.Errorf("expected 400 with Sec-WebSocket-Version, got %d %v", resp.StatusCode, resp.Header)
+	}
+	conn.Close()
+	header = webSocketHeader()
+	header.Set("Origin", "http://other.example.com")
+	conn, _, resp = dialWebSocket(t, addr, header)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for cross origin request, got %d", resp.StatusCode)
+	}
+	conn.Close()
+}
+
+func TestCloseWebSockets(t *testing.T) {
+	var root = NewRootRouter()
+	var returned = make(chan struct{})
+	root.AddChild(NewWebSocketRouter("ws", func(conn *WebSocketConn, context *Context) {
+		defer close(returned)
+		for {
+			var _, _, err = conn.ReadMessage()
+			if err != nil {
+				return
+			}
+		}
+	}))
+	var processor = newTestProcessor(t, root)
+	var server = httptest.NewServer(testHandler(processor))
+	defer server.Close()
+	var addr = server.Listener.Addr().String()
+	var conn, reader, resp = dialWebSocket(t, addr, webSocketHeader())
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %d", resp.StatusCode)
+	}
+	defer conn.Close()
+	var closed = make(chan struct{})
+	go func() {
+		processor.CloseWebSockets()
+		close(closed)
+	}()
+	expectClose(t, reader, CloseGoingAway)
+	writeTestFrame(t, conn, true, WebSocketClose, closePayload(CloseGoingAway, ""), true)
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("CloseWebSockets did not return")
+	}
+	select {
+	case <-returned:
+	default:
+		t.Error("CloseWebSockets returned before the handler")
+	}
+	//之后的连接在握手后立即关闭
+	conn2, reader2, resp := dialWebSocket(t, addr, webSocketHeader())
+	defer conn2.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %d", resp.StatusCode)
+	}
+	expectClose(t, reader2, CloseGoingAway)
+}